@@ -0,0 +1,48 @@
+package main
+
+import (
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+    httpServerDuration = promauto.NewHistogramVec(
+        prometheus.HistogramOpts{
+            Name: "http_server_requests_seconds",
+            Help: "Duration of HTTP requests served by this instance",
+        },
+        []string{"method", "route", "status"},
+    )
+
+    authorizeRequests = promauto.NewCounterVec(
+        prometheus.CounterOpts{
+            Name: "payment_authorize_requests_total",
+            Help: "Number of /authorize requests by outcome",
+        },
+        []string{"outcome"},
+    )
+
+    authorizeDuration = promauto.NewHistogram(
+        prometheus.HistogramOpts{
+            Name: "payment_authorize_duration_seconds",
+            Help: "Duration of /authorize requests",
+        },
+    )
+)
+
+// httpMetricsMiddleware records http_server_requests_seconds for every
+// request, labeled by route, method, and response status.
+func httpMetricsMiddleware(c *gin.Context) {
+    start := time.Now()
+    c.Next()
+
+    httpServerDuration.WithLabelValues(
+        c.Request.Method,
+        c.FullPath(),
+        strconv.Itoa(c.Writer.Status()),
+    ).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+    "os"
+
+    "github.com/rs/zerolog"
+)
+
+// logger is this service's structured JSON logger, replacing the
+// standard library's log package so an external log pipeline gets
+// consistent JSON fields instead of parsing free-text messages.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Str("service", "credit-card-authorizer").Logger()
@@ -1,14 +1,21 @@
 package main
 
 import (
+    "context"
     "math/rand"
     "net/http"
     "os"
+    "os/signal"
     "regexp"
+    "syscall"
     "time"
 
     "github.com/gin-gonic/gin"
     "github.com/google/uuid"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+    "credit-card-authorizer/telemetry"
 )
 
 type AuthRequest struct {
@@ -32,8 +39,20 @@ func main() {
         port = "8080"
     }
 
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    shutdown, err := telemetry.Init(ctx, "credit-card-authorizer")
+    if err != nil {
+        logger.Fatal().Err(err).Msg("failed to initialize telemetry")
+    }
+    defer shutdown(context.Background())
+
     router := gin.Default()
+    router.Use(otelgin.Middleware("credit-card-authorizer"))
+    router.Use(httpMetricsMiddleware)
     router.GET("/health", healthCheck)
+    router.GET("/metrics", gin.WrapH(promhttp.Handler()))
     router.POST("/authorize", authorizePayment)
 
     router.Run(":" + port)
@@ -44,6 +63,13 @@ func healthCheck(c *gin.Context) {
 }
 
 func authorizePayment(c *gin.Context) {
+    start := time.Now()
+    outcome := "error"
+    defer func() {
+        authorizeDuration.Observe(time.Since(start).Seconds())
+        authorizeRequests.WithLabelValues(outcome).Inc()
+    }()
+
     var req AuthRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
@@ -54,6 +80,7 @@ func authorizePayment(c *gin.Context) {
     pattern := `^\d{4}-\d{4}-\d{4}-\d{4}$`
     matched, _ := regexp.MatchString(pattern, req.CreditCardNumber)
     if !matched {
+        outcome = "invalid_format"
         c.JSON(http.StatusBadRequest, gin.H{"error": "invalid credit card format", "message": "Format must be XXXX-XXXX-XXXX-XXXX"})
         return
     }
@@ -62,12 +89,14 @@ func authorizePayment(c *gin.Context) {
     isAuthorized := rand.Float32() < 0.9
 
     if isAuthorized {
+        outcome = "authorized"
         c.JSON(http.StatusOK, AuthResponse{
             Status:        "Authorized",
             TransactionID: uuid.New().String(),
             Message:       "Payment authorized successfully",
         })
     } else {
+        outcome = "declined"
         c.JSON(http.StatusPaymentRequired, AuthResponse{
             Status:  "Declined",
             Message: "Card declined by issuer",
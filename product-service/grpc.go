@@ -0,0 +1,95 @@
+package main
+
+import (
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"product-service/grpcserver"
+	"product-service/productpb"
+	"product-service/search"
+)
+
+// grpcPort is the port the gRPC server listens on, alongside the existing
+// Gin HTTP server on :8080.
+const grpcPort = ":9090"
+
+// productStore adapts the package-level products sync.Map and searchIndex
+// to the grpcserver.Store interface, so the gRPC and REST transports share
+// one backing store.
+type productStore struct{}
+
+func (productStore) Load(productID int) (*productpb.Product, bool) {
+	value, exists := products.Load(productID)
+	if !exists {
+		return nil, false
+	}
+	return toProto(value.(Product)), true
+}
+
+func (productStore) Store(productID int, p *productpb.Product) {
+	product := fromProto(p)
+	products.Store(productID, product)
+	indexProduct(product)
+}
+
+func (productStore) Search(q search.Query) ([]search.Result, int) {
+	return searchIndex.Search(q)
+}
+
+func toProto(p Product) *productpb.Product {
+	return &productpb.Product{
+		ProductId:    int32(p.ProductID),
+		Name:         p.Name,
+		Category:     p.Category,
+		Description:  p.Description,
+		Brand:        p.Brand,
+		Sku:          p.SKU,
+		Manufacturer: p.Manufacturer,
+		CategoryId:   int32(p.CategoryID),
+		Weight:       int32(p.Weight),
+		SomeOtherId:  int32(p.SomeOtherID),
+	}
+}
+
+func fromProto(p *productpb.Product) Product {
+	return Product{
+		ProductID:    int(p.ProductId),
+		Name:         p.Name,
+		Category:     p.Category,
+		Description:  p.Description,
+		Brand:        p.Brand,
+		SKU:          p.Sku,
+		Manufacturer: p.Manufacturer,
+		CategoryID:   int(p.CategoryId),
+		Weight:       int(p.Weight),
+		SomeOtherID:  int(p.SomeOtherId),
+	}
+}
+
+// startGRPCServer starts the gRPC server on grpcPort in the background. It
+// registers the ProductService alongside the standard gRPC health service
+// so orchestrators can probe readiness the same way they would for HTTP.
+func startGRPCServer() {
+	lis, err := net.Listen("tcp", grpcPort)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", grpcPort, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	productpb.RegisterProductServiceServer(grpcServer, grpcserver.New(productStore{}))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("product.ProductService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	log.Printf("Product Service gRPC server starting on %s", grpcPort)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server stopped: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,87 @@
+// Package grpcserver implements the ProductService gRPC API defined in
+// proto/product.proto. It reads from and writes to the same sync.Map the
+// HTTP handlers in package main use, so the two transports stay consistent.
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"product-service/productpb"
+	"product-service/search"
+)
+
+// Store is the subset of package main's product storage the gRPC server
+// needs. main.go's products sync.Map plus searchIndex satisfy it directly.
+// Product is passed by pointer, not value, because productpb.Product
+// embeds a protoimpl.MessageState that itself contains a sync.Mutex --
+// copying one is a vet error (and generated proto messages are never
+// meant to be copied).
+type Store interface {
+	Load(productID int) (*productpb.Product, bool)
+	Store(productID int, product *productpb.Product)
+	Search(q search.Query) (results []search.Result, totalFound int)
+}
+
+// Server implements productpb.ProductServiceServer.
+type Server struct {
+	productpb.UnimplementedProductServiceServer
+	store Store
+}
+
+// New returns a Server backed by store.
+func New(store Store) *Server {
+	return &Server{store: store}
+}
+
+// GetProduct looks up a single product by ID.
+func (s *Server) GetProduct(ctx context.Context, req *productpb.GetProductRequest) (*productpb.Product, error) {
+	product, ok := s.store.Load(int(req.ProductId))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "product %d not found", req.ProductId)
+	}
+	return product, nil
+}
+
+// PostProductDetails creates or replaces a product's details.
+func (s *Server) PostProductDetails(ctx context.Context, req *productpb.PostProductDetailsRequest) (*productpb.PostProductDetailsResponse, error) {
+	if req.Product == nil {
+		return nil, status.Error(codes.InvalidArgument, "product is required")
+	}
+	s.store.Store(int(req.ProductId), req.Product)
+	return &productpb.PostProductDetailsResponse{}, nil
+}
+
+// SearchProducts streams ranked matches instead of returning one page, so a
+// caller asking for a large limit doesn't force the server to buffer it all
+// before the first byte goes out.
+func (s *Server) SearchProducts(req *productpb.SearchProductsRequest, stream productpb.ProductService_SearchProductsServer) error {
+	sortBy := search.SortRelevance
+	switch req.Sort {
+	case "weight":
+		sortBy = search.SortWeight
+	case "name":
+		sortBy = search.SortName
+	}
+
+	matches, _ := s.store.Search(search.Query{
+		Text:     req.Q,
+		Category: req.Category,
+		Sort:     sortBy,
+		Limit:    int(req.Limit),
+		Offset:   int(req.Offset),
+	})
+
+	for _, m := range matches {
+		product, ok := s.store.Load(m.ProductID)
+		if !ok {
+			continue
+		}
+		if err := stream.Send(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
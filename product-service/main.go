@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
-	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"product-service/search"
+	"product-service/telemetry"
 )
 
 // Product represents data about a product.
@@ -27,6 +35,21 @@ type Product struct {
 // products sync.Map to store product data (productID -> Product)
 var products sync.Map
 
+// searchIndex is the in-memory inverted index backing GET /products/search.
+var searchIndex = search.NewIndex()
+
+// indexProduct adds or updates product in the search index.
+func indexProduct(product Product) {
+	searchIndex.Put(search.Document{
+		ID:          product.ProductID,
+		Name:        product.Name,
+		Category:    product.Category,
+		Brand:       product.Brand,
+		Description: product.Description,
+		Weight:      product.Weight,
+	})
+}
+
 // generateProducts creates 100,000 products with varied data
 func generateProducts() {
 	brands := []string{"Alpha", "Beta", "Gamma", "Delta", "Epsilon", "Zeta", "Eta", "Theta"}
@@ -46,16 +69,33 @@ func generateProducts() {
 			SomeOtherID:  i * 10,
 		}
 		products.Store(i, product)
+		indexProduct(product)
 	}
 
 	fmt.Printf("Generated 100,000 products\n")
 }
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdown, err := telemetry.Init(ctx, "product-service")
+	if err != nil {
+		log.Fatalf("failed to initialize telemetry: %v", err)
+	}
+	defer shutdown(context.Background())
+	initHTTPMetrics()
+
 	// Generate 100,000 products at startup
 	generateProducts()
 
+	// Start the gRPC server (ProductService + health) on a second port,
+	// sharing the same sync.Map and search index as the HTTP handlers.
+	startGRPCServer()
+
 	router := gin.Default()
+	router.Use(otelgin.Middleware("product-service"))
+	router.Use(httpMetricsMiddleware)
 	router.GET("/health", healthCheck)
 	router.GET("/products/:productId", getProductByID)
 	router.POST("/products/:productId/details", postProductDetails)
@@ -113,54 +153,71 @@ func postProductDetails(c *gin.Context) {
 
 	// Store in the sync.Map using the ID from the URL
 	products.Store(idInt, newProduct)
+	searchIndex.Put(search.Document{
+		ID:          idInt,
+		Name:        newProduct.Name,
+		Category:    newProduct.Category,
+		Brand:       newProduct.Brand,
+		Description: newProduct.Description,
+		Weight:      newProduct.Weight,
+	})
 
 	// Return 204 No Content
 	c.Status(http.StatusNoContent)
 }
 
-// searchProducts searches through products by name and category
+// searchProducts runs a query against the in-memory inverted index and
+// returns BM25-ranked results over the full 100k-product catalog, not just
+// a fixed prefix of it.
 func searchProducts(c *gin.Context) {
 	query := c.Query("q")
-	if query == "" {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"message": "query parameter 'q' is required"})
+	category := c.Query("category")
+	if query == "" && category == "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"message": "query parameter 'q' or 'category' is required"})
 		return
 	}
 
-	// Convert query to lowercase for case-insensitive matching
-	queryLower := strings.ToLower(query)
-
-	var results []Product
-	checkedCount := 0
-	maxCheck := 100  // Check exactly 100 products
-	maxResults := 20 // Return max 20 results
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
 
-	// Iterate through products using Range and check exactly 100
-	products.Range(func(key, value interface{}) bool {
-		if checkedCount >= maxCheck {
-			return false // Stop iteration
+	offset := 0
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
 		}
-		checkedCount++
+	}
 
-		product := value.(Product)
+	sortBy := search.SortRelevance
+	switch c.Query("sort") {
+	case "weight":
+		sortBy = search.SortWeight
+	case "name":
+		sortBy = search.SortName
+	}
 
-		// Check if query matches name or category (case-insensitive)
-		nameLower := strings.ToLower(product.Name)
-		categoryLower := strings.ToLower(product.Category)
+	matches, totalFound := searchIndex.Search(search.Query{
+		Text:     query,
+		Category: category,
+		Sort:     sortBy,
+		Limit:    limit,
+		Offset:   offset,
+	})
 
-		if strings.Contains(nameLower, queryLower) || strings.Contains(categoryLower, queryLower) {
-			results = append(results, product)
-			if len(results) >= maxResults {
-				return false // Stop iteration
-			}
+	results := make([]Product, 0, len(matches))
+	for _, m := range matches {
+		value, exists := products.Load(m.ProductID)
+		if !exists {
+			continue
 		}
+		results = append(results, value.(Product))
+	}
 
-		return true // Continue iteration
-	})
-
-	// Return response
 	c.IndentedJSON(http.StatusOK, gin.H{
 		"products":    results,
-		"total_found": len(results),
-		"checked":     checkedCount,
+		"total_found": totalFound,
 	})
 }
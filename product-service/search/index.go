@@ -0,0 +1,291 @@
+// Package search provides an in-memory inverted index over product
+// listings, replacing a linear scan that only ever inspected a fixed
+// prefix of the product set. Postings are plain sorted ID slices rather
+// than a compressed bitmap, which is simple to reason about and more than
+// fast enough at the 100k-product scale this service runs at.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Document is the subset of product fields the index cares about. Callers
+// (the product service's handlers) build one of these from a Product.
+type Document struct {
+	ID          int
+	Name        string
+	Category    string
+	Brand       string
+	Description string
+	Weight      int
+}
+
+// Sort selects the ordering applied to search results.
+type Sort string
+
+const (
+	SortRelevance Sort = "relevance"
+	SortWeight    Sort = "weight"
+	SortName      Sort = "name"
+)
+
+// BM25 tuning parameters. k1 controls term-frequency saturation, b controls
+// how strongly document length normalizes against the average.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// docEntry holds the per-document bookkeeping needed to score it.
+type docEntry struct {
+	name     string
+	category string
+	weight   int
+	length   int // token count across indexed fields, captured at index time
+}
+
+// Index is a thread-safe inverted index. The zero value is ready to use.
+type Index struct {
+	mu sync.RWMutex
+
+	postings map[string][]int       // token -> sorted product IDs
+	termFreq map[string]map[int]int // token -> product ID -> term frequency
+	docs     map[int]docEntry
+
+	totalDocLen int64 // sum of all docEntry.length, for avgDocLen
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string][]int),
+		termFreq: make(map[string]map[int]int),
+		docs:     make(map[int]docEntry),
+	}
+}
+
+// Put inserts or updates doc in the index. Updating a previously indexed
+// product first removes its old postings so stale tokens don't linger.
+func (idx *Index) Put(doc Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, exists := idx.docs[doc.ID]; exists {
+		idx.removeLocked(doc.ID)
+	}
+
+	fields := strings.Join([]string{doc.Name, doc.Category, doc.Brand, doc.Description}, " ")
+	tokens := Tokenize(fields)
+
+	freq := make(map[string]int, len(tokens))
+	for _, tok := range tokens {
+		freq[tok]++
+	}
+
+	for tok, count := range freq {
+		idx.postings[tok] = insertSorted(idx.postings[tok], doc.ID)
+		if idx.termFreq[tok] == nil {
+			idx.termFreq[tok] = make(map[int]int)
+		}
+		idx.termFreq[tok][doc.ID] = count
+	}
+
+	idx.docs[doc.ID] = docEntry{
+		name:     doc.Name,
+		category: doc.Category,
+		weight:   doc.Weight,
+		length:   len(tokens),
+	}
+	idx.totalDocLen += int64(len(tokens))
+}
+
+// removeLocked deletes id's postings and term frequencies. Callers must
+// hold idx.mu for writing.
+func (idx *Index) removeLocked(id int) {
+	entry, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for tok, byDoc := range idx.termFreq {
+		if _, ok := byDoc[id]; ok {
+			delete(byDoc, id)
+			idx.postings[tok] = removeSorted(idx.postings[tok], id)
+			if len(byDoc) == 0 {
+				delete(idx.termFreq, tok)
+				delete(idx.postings, tok)
+			}
+		}
+	}
+	idx.totalDocLen -= int64(entry.length)
+	delete(idx.docs, id)
+}
+
+// Query describes a search request against the index.
+type Query struct {
+	Text     string
+	Category string
+	Sort     Sort
+	Limit    int
+	Offset   int
+}
+
+// Result is a single scored match.
+type Result struct {
+	ProductID int
+	Score     float64
+}
+
+// Search executes a boolean AND over the query's tokens against the
+// postings, optionally filtered by category, scores matches with BM25, and
+// returns a paginated, sorted slice alongside the true total match count.
+func (idx *Index) Search(q Query) (results []Result, totalFound int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	tokens := Tokenize(q.Text)
+	candidates := idx.matchLocked(tokens)
+
+	if q.Category != "" {
+		filtered := candidates[:0:0]
+		for _, id := range candidates {
+			if strings.EqualFold(idx.docs[id].category, q.Category) {
+				filtered = append(filtered, id)
+			}
+		}
+		candidates = filtered
+	}
+
+	totalFound = len(candidates)
+
+	scored := make([]Result, 0, len(candidates))
+	avgDocLen := idx.avgDocLenLocked()
+	for _, id := range candidates {
+		scored = append(scored, Result{ProductID: id, Score: idx.bm25Locked(tokens, id, avgDocLen)})
+	}
+
+	switch q.Sort {
+	case SortWeight:
+		sort.SliceStable(scored, func(i, j int) bool {
+			return idx.docs[scored[i].ProductID].weight > idx.docs[scored[j].ProductID].weight
+		})
+	case SortName:
+		sort.SliceStable(scored, func(i, j int) bool {
+			return idx.docs[scored[i].ProductID].name < idx.docs[scored[j].ProductID].name
+		})
+	default: // SortRelevance
+		sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	}
+
+	return paginate(scored, q.Offset, q.Limit), totalFound
+}
+
+// matchLocked returns the sorted product IDs matching the AND of tokens. An
+// empty token list matches every indexed document (bare category browse).
+func (idx *Index) matchLocked(tokens []string) []int {
+	if len(tokens) == 0 {
+		all := make([]int, 0, len(idx.docs))
+		for id := range idx.docs {
+			all = append(all, id)
+		}
+		sort.Ints(all)
+		return all
+	}
+
+	result := idx.postings[tokens[0]]
+	for _, tok := range tokens[1:] {
+		result = intersectSorted(result, idx.postings[tok])
+		if len(result) == 0 {
+			break
+		}
+	}
+	// Copy out since intersectSorted may return a postings slice directly.
+	out := make([]int, len(result))
+	copy(out, result)
+	return out
+}
+
+func (idx *Index) avgDocLenLocked() float64 {
+	if len(idx.docs) == 0 {
+		return 0
+	}
+	return float64(idx.totalDocLen) / float64(len(idx.docs))
+}
+
+// bm25Locked scores document id against tokens using the Robertson-Sparck
+// Jones BM25 formula with k1=1.2, b=0.75.
+func (idx *Index) bm25Locked(tokens []string, id int, avgDocLen float64) float64 {
+	entry := idx.docs[id]
+	n := float64(len(idx.docs))
+
+	var score float64
+	for _, tok := range tokens {
+		tf := float64(idx.termFreq[tok][id])
+		if tf == 0 {
+			continue
+		}
+		df := float64(len(idx.postings[tok]))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		denom := tf + bm25K1*(1-bm25B+bm25B*float64(entry.length)/avgDocLen)
+		score += idf * (tf * (bm25K1 + 1) / denom)
+	}
+	return score
+}
+
+func paginate(results []Result, offset, limit int) []Result {
+	if offset >= len(results) {
+		return nil
+	}
+	end := len(results)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return results[offset:end]
+}
+
+func insertSorted(ids []int, id int) []int {
+	i := sort.SearchInts(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return ids
+	}
+	ids = append(ids, 0)
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	return ids
+}
+
+func removeSorted(ids []int, id int) []int {
+	i := sort.SearchInts(ids, id)
+	if i >= len(ids) || ids[i] != id {
+		return ids
+	}
+	return append(ids[:i], ids[i+1:]...)
+}
+
+// intersectSorted returns the sorted intersection of two sorted ID slices.
+func intersectSorted(a, b []int) []int {
+	out := make([]int, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,220 @@
+package search
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Put(Document{ID: 1, Name: "Red Running Shoes", Category: "Footwear", Brand: "Acme", Description: "Lightweight shoes for running", Weight: 300})
+	idx.Put(Document{ID: 2, Name: "Blue Running Jacket", Category: "Apparel", Brand: "Acme", Description: "Windproof jacket for running", Weight: 500})
+	idx.Put(Document{ID: 3, Name: "Red Hiking Boots", Category: "Footwear", Brand: "Trailblazer", Description: "Durable boots for hiking trails", Weight: 900})
+	idx.Put(Document{ID: 4, Name: "Camping Tent", Category: "Gear", Brand: "Trailblazer", Description: "Two-person tent for camping", Weight: 2200})
+	return idx
+}
+
+func ids(results []Result) []int {
+	out := make([]int, len(results))
+	for i, r := range results {
+		out[i] = r.ProductID
+	}
+	return out
+}
+
+func TestIndex_Search_BooleanAND(t *testing.T) {
+	idx := newTestIndex()
+
+	results, total := idx.Search(Query{Text: "red running"})
+	if total != 1 || !equalInts(ids(results), []int{1}) {
+		t.Fatalf("Search(\"red running\") = %v (total %d), want [1] (only doc 1 has both tokens)", ids(results), total)
+	}
+
+	results, total = idx.Search(Query{Text: "running"})
+	got := ids(results)
+	sort.Ints(got)
+	if total != 2 || !equalInts(got, []int{1, 2}) {
+		t.Fatalf("Search(\"running\") = %v (total %d), want [1 2]", got, total)
+	}
+
+	results, total = idx.Search(Query{Text: "red"})
+	got = ids(results)
+	sort.Ints(got)
+	if total != 2 || !equalInts(got, []int{1, 3}) {
+		t.Fatalf("Search(\"red\") = %v (total %d), want [1 3]", got, total)
+	}
+}
+
+func TestIndex_Search_UnknownTokenMatchesNothing(t *testing.T) {
+	idx := newTestIndex()
+
+	results, total := idx.Search(Query{Text: "submarine"})
+	if total != 0 || len(results) != 0 {
+		t.Fatalf("Search(\"submarine\") = %v (total %d), want no matches", ids(results), total)
+	}
+}
+
+func TestIndex_Search_CategoryFilter(t *testing.T) {
+	idx := newTestIndex()
+
+	results, total := idx.Search(Query{Category: "Footwear"})
+	got := ids(results)
+	sort.Ints(got)
+	if total != 2 || !equalInts(got, []int{1, 3}) {
+		t.Fatalf("Search(category=Footwear) = %v (total %d), want [1 3]", got, total)
+	}
+}
+
+func TestIndex_Search_RelevanceRanksMoreFrequentTermHigher(t *testing.T) {
+	idx := NewIndex()
+	// doc 1 mentions "trail" once, doc 2 mentions it three times; BM25
+	// should rank doc 2 above doc 1 for a "trail" query.
+	idx.Put(Document{ID: 1, Name: "Trail Map", Category: "Gear", Description: "A map of the trail"})
+	idx.Put(Document{ID: 2, Name: "Trail Guide", Category: "Gear", Description: "trail trail trail running guide"})
+
+	results, total := idx.Search(Query{Text: "trail", Sort: SortRelevance})
+	if total != 2 || len(results) != 2 {
+		t.Fatalf("Search(\"trail\") total = %d, results = %v, want 2", total, results)
+	}
+	if results[0].ProductID != 2 {
+		t.Fatalf("top result = product %d, want 2 (higher term frequency)", results[0].ProductID)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Fatalf("scores = %v, want results[0].Score > results[1].Score", results)
+	}
+}
+
+func TestIndex_Search_SortByWeightAndName(t *testing.T) {
+	idx := newTestIndex()
+
+	results, _ := idx.Search(Query{Sort: SortWeight})
+	if got := ids(results); !equalInts(got, []int{4, 3, 2, 1}) {
+		t.Fatalf("Search(sort=weight) = %v, want [4 3 2 1] (heaviest first)", got)
+	}
+
+	results, _ = idx.Search(Query{Sort: SortName})
+	got := ids(results)
+	want := []int{2, 4, 3, 1} // "Blue...", "Camping...", "Red Hiking...", "Red Running..."
+	if !equalInts(got, want) {
+		t.Fatalf("Search(sort=name) = %v, want %v", got, want)
+	}
+}
+
+func TestIndex_Search_Pagination(t *testing.T) {
+	idx := newTestIndex()
+
+	results, total := idx.Search(Query{Sort: SortName, Limit: 2, Offset: 1})
+	if total != 4 {
+		t.Fatalf("totalFound = %d, want 4 (pagination shouldn't affect it)", total)
+	}
+	if got := ids(results); !equalInts(got, []int{4, 3}) {
+		t.Fatalf("Search(limit=2, offset=1) = %v, want [4 3]", got)
+	}
+
+	results, _ = idx.Search(Query{Sort: SortName, Offset: 10})
+	if len(results) != 0 {
+		t.Fatalf("Search(offset=10) = %v, want empty (offset past the end)", results)
+	}
+}
+
+func TestIndex_Put_UpdateRemovesStalePostings(t *testing.T) {
+	idx := NewIndex()
+	idx.Put(Document{ID: 1, Name: "Red Shirt", Category: "Apparel"})
+
+	if results, total := idx.Search(Query{Text: "red"}); total != 1 || ids(results)[0] != 1 {
+		t.Fatalf("before update: Search(\"red\") = %v (total %d), want [1]", ids(results), total)
+	}
+
+	idx.Put(Document{ID: 1, Name: "Blue Shirt", Category: "Apparel"})
+
+	if results, total := idx.Search(Query{Text: "red"}); total != 0 || len(results) != 0 {
+		t.Fatalf("after update: Search(\"red\") = %v (total %d), want no matches (stale posting not removed)", ids(results), total)
+	}
+	if results, total := idx.Search(Query{Text: "blue"}); total != 1 || ids(results)[0] != 1 {
+		t.Fatalf("after update: Search(\"blue\") = %v (total %d), want [1]", ids(results), total)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// benchAdjectives/benchNouns/benchCategories/benchBrands feed a
+// deterministic generator for the 100k-document benchmark corpus below.
+// The vocabulary is sized so a two-term query intersects down to a
+// realistically small candidate set (as a real product catalog's
+// distinct names would), rather than a handful of words repeated across
+// every document, which would make every query touch most of the index
+// regardless of how selective the inverted index itself is.
+var (
+	benchAdjectives = generateWords("adj", 40)
+	benchNouns      = generateWords("noun", 60)
+	benchCategories = []string{"Footwear", "Apparel", "Gear", "Accessories", "Electronics", "Home", "Outdoor", "Toys"}
+	benchBrands     = generateWords("brand", 30)
+)
+
+func generateWords(prefix string, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return words
+}
+
+func buildBenchIndex(n int) *Index {
+	idx := NewIndex()
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < n; i++ {
+		idx.Put(Document{
+			ID:          i,
+			Name:        fmt.Sprintf("%s %s", benchAdjectives[rng.Intn(len(benchAdjectives))], benchNouns[rng.Intn(len(benchNouns))]),
+			Category:    benchCategories[rng.Intn(len(benchCategories))],
+			Brand:       benchBrands[rng.Intn(len(benchBrands))],
+			Description: fmt.Sprintf("%s %s for everyday use, %s construction", benchAdjectives[rng.Intn(len(benchAdjectives))], benchNouns[rng.Intn(len(benchNouns))], benchAdjectives[rng.Intn(len(benchAdjectives))]),
+			Weight:      rng.Intn(3000),
+		})
+	}
+	return idx
+}
+
+// BenchmarkIndex_Search_100kDocs searches a 100,000-document index and
+// reports p50/p99 latency alongside the standard ns/op average, since an
+// average can hide a long tail that an index serving real traffic cares
+// about. Run with: go test ./search/ -bench=100kDocs -benchtime=2000x
+func BenchmarkIndex_Search_100kDocs(b *testing.B) {
+	idx := buildBenchIndex(100_000)
+	queries := []Query{
+		{Text: benchAdjectives[3] + " " + benchNouns[7]},
+		{Text: benchAdjectives[11], Category: benchCategories[2]},
+		{Text: benchNouns[21] + " " + benchAdjectives[5], Sort: SortWeight},
+		{Text: benchAdjectives[17] + " " + benchNouns[33], Sort: SortName, Limit: 20},
+	}
+
+	durations := make([]time.Duration, 0, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		start := time.Now()
+		idx.Search(q)
+		durations = append(durations, time.Since(start))
+	}
+	b.StopTimer()
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	p50 := durations[len(durations)*50/100]
+	p99 := durations[min(len(durations)*99/100, len(durations)-1)]
+
+	b.ReportMetric(float64(p50.Microseconds()), "p50-us/op")
+	b.ReportMetric(float64(p99.Microseconds()), "p99-us/op")
+}
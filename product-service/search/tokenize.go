@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwords are common English words excluded from the index and from
+// query tokenization since they carry no discriminative weight.
+var stopwords = map[string]struct{}{
+	"a": {}, "an": {}, "the": {},
+	"of": {}, "and": {}, "or": {},
+	"in": {}, "on": {}, "for": {}, "to": {},
+	"is": {}, "with": {}, "by": {}, "at": {},
+}
+
+// Tokenize lowercases text (Unicode-aware), folds it to plain ASCII, splits
+// on runs of non-alphanumeric characters, and drops stopwords. The same
+// function is used to tokenize both indexed documents and search queries
+// so the two stay consistent.
+func Tokenize(text string) []string {
+	folded := foldASCII(strings.ToLower(text))
+
+	var tokens []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() == 0 {
+			return
+		}
+		tok := b.String()
+		b.Reset()
+		if _, stop := stopwords[tok]; stop {
+			return
+		}
+		tokens = append(tokens, tok)
+	}
+
+	for _, r := range folded {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// foldASCII strips common Latin diacritics (e.g. "café" -> "cafe") using a
+// small direct table, avoiding a dependency on golang.org/x/text just for
+// this. Runes outside the table pass through unchanged.
+func foldASCII(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if folded, ok := asciiFoldTable[r]; ok {
+			b.WriteRune(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var asciiFoldTable = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ñ': 'n', 'ç': 'c', 'ý': 'y',
+}
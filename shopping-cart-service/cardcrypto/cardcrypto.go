@@ -0,0 +1,70 @@
+// Package cardcrypto encrypts the credit card number carried in
+// WarehouseOrder messages before checkout publishes them, so the PAN
+// doesn't sit in plaintext in the durable stores those messages pass
+// through (the Postgres outbox's payload column, persistent RabbitMQ
+// messages, file-backed JetStream). warehouse-service decrypts the
+// token under the same shared key just before re-authorizing payment.
+package cardcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// Cipher seals and opens card numbers with AES-256-GCM under a single
+// key, shared out-of-band (e.g. PAYMENT_ENCRYPTION_KEY) between
+// shopping-cart-service and warehouse-service.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New builds a Cipher from keyHex, a hex-encoded 32-byte AES-256 key
+// (e.g. the output of `openssl rand -hex 32`).
+func New(keyHex string) (*Cipher, error) {
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("cardcrypto: decode key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cardcrypto: build AES cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cardcrypto: build GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt returns plaintext sealed under a fresh random nonce, encoded
+// as base64(nonce || ciphertext).
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("cardcrypto: generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt.
+func (c *Cipher) Decrypt(token string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("cardcrypto: decode token: %w", err)
+	}
+	if len(raw) < c.aead.NonceSize() {
+		return "", fmt.Errorf("cardcrypto: token shorter than nonce")
+	}
+	nonce, ciphertext := raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("cardcrypto: decrypt token: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,84 @@
+package cartstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is a mutex-protected in-memory Store. Carts don't survive a
+// restart; use PostgresStore when they need to.
+type MemoryStore struct {
+	mu    sync.Mutex
+	carts map[string]Cart
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{carts: make(map[string]Cart)}
+}
+
+func (s *MemoryStore) Create(ctx context.Context, customerID string) (Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart := Cart{
+		CartID:     uuid.New().String(),
+		CustomerID: customerID,
+		Items:      []Item{},
+		CreatedAt:  time.Now(),
+		Version:    1,
+	}
+	s.carts[cart.CartID] = cart
+	return cart, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, cartID string) (Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart, ok := s.carts[cartID]
+	if !ok {
+		return Cart{}, ErrNotFound
+	}
+	return cart, nil
+}
+
+func (s *MemoryStore) AddItem(ctx context.Context, cartID, productID string, quantity int) (Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart, ok := s.carts[cartID]
+	if !ok {
+		return Cart{}, ErrNotFound
+	}
+
+	found := false
+	for i := range cart.Items {
+		if cart.Items[i].ProductID == productID {
+			cart.Items[i].Quantity += quantity
+			found = true
+			break
+		}
+	}
+	if !found {
+		cart.Items = append(cart.Items, Item{ProductID: productID, Quantity: quantity})
+	}
+	cart.Version++
+
+	s.carts[cartID] = cart
+	return cart, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, cartID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.carts[cartID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.carts, cartID)
+	return nil
+}
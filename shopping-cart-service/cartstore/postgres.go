@@ -0,0 +1,277 @@
+package cartstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// schemaSQL creates the carts/cart_items tables if they don't already
+// exist. There's no migration history to manage yet, so a single
+// idempotent script run at startup is enough.
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS carts (
+    cart_id     UUID PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    version     INT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    cart_id    UUID NOT NULL REFERENCES carts(cart_id) ON DELETE CASCADE,
+    product_id TEXT NOT NULL,
+    quantity   INT NOT NULL,
+    PRIMARY KEY (cart_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id           UUID PRIMARY KEY,
+    aggregate_id UUID NOT NULL,
+    payload      JSONB NOT NULL,
+    headers      JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ
+);
+`
+
+// maxVersionRetries bounds how many times AddItem retries after losing an
+// optimistic-concurrency race before giving up.
+const maxVersionRetries = 5
+
+// errVersionConflict signals that the carts.version guard didn't match
+// and the caller should retry with a fresh read.
+var errVersionConflict = errors.New("cartstore: version conflict")
+
+// PostgresStore is a Store backed by Postgres via pgx. AddItem uses the
+// carts.version column as an optimistic-concurrency guard: it reads the
+// current version, writes the item and bumps the version in the same
+// transaction, and retries from scratch if another writer got there
+// first.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore connects to dsn and ensures the schema exists.
+func NewPostgresStore(ctx context.Context, dsn string) (*PostgresStore, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("cartstore: connect: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, schemaSQL); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("cartstore: migrate schema: %w", err)
+	}
+
+	return &PostgresStore{pool: pool}, nil
+}
+
+// Close releases the underlying connection pool.
+func (s *PostgresStore) Close() {
+	s.pool.Close()
+}
+
+func (s *PostgresStore) Create(ctx context.Context, customerID string) (Cart, error) {
+	cart := Cart{
+		CartID:     uuid.New().String(),
+		CustomerID: customerID,
+		Items:      []Item{},
+		CreatedAt:  time.Now(),
+		Version:    1,
+	}
+
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO carts (cart_id, customer_id, created_at, version) VALUES ($1, $2, $3, $4)`,
+		cart.CartID, cart.CustomerID, cart.CreatedAt, cart.Version,
+	)
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: create cart: %w", err)
+	}
+	return cart, nil
+}
+
+func (s *PostgresStore) Get(ctx context.Context, cartID string) (Cart, error) {
+	var cart Cart
+	err := s.pool.QueryRow(ctx,
+		`SELECT cart_id, customer_id, created_at, version FROM carts WHERE cart_id = $1`, cartID,
+	).Scan(&cart.CartID, &cart.CustomerID, &cart.CreatedAt, &cart.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Cart{}, ErrNotFound
+	}
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: get cart: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT product_id, quantity FROM cart_items WHERE cart_id = $1`, cartID)
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: get items: %w", err)
+	}
+	defer rows.Close()
+
+	cart.Items = []Item{}
+	for rows.Next() {
+		var item Item
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return Cart{}, fmt.Errorf("cartstore: scan item: %w", err)
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return Cart{}, fmt.Errorf("cartstore: get items: %w", err)
+	}
+
+	return cart, nil
+}
+
+func (s *PostgresStore) AddItem(ctx context.Context, cartID, productID string, quantity int) (Cart, error) {
+	for attempt := 0; attempt < maxVersionRetries; attempt++ {
+		cart, err := s.addItemOnce(ctx, cartID, productID, quantity)
+		if errors.Is(err, errVersionConflict) {
+			continue
+		}
+		return cart, err
+	}
+	return Cart{}, fmt.Errorf("cartstore: add item: too many version conflicts on cart %s", cartID)
+}
+
+func (s *PostgresStore) addItemOnce(ctx context.Context, cartID, productID string, quantity int) (Cart, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var version int
+	err = tx.QueryRow(ctx, `SELECT version FROM carts WHERE cart_id = $1`, cartID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Cart{}, ErrNotFound
+	}
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: read version: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity
+	`, cartID, productID, quantity)
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: upsert item: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE carts SET version = version + 1 WHERE cart_id = $1 AND version = $2`, cartID, version)
+	if err != nil {
+		return Cart{}, fmt.Errorf("cartstore: bump version: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Someone else updated the cart between our read and our write;
+		// roll back and let the caller retry with a fresh version.
+		return Cart{}, errVersionConflict
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Cart{}, fmt.Errorf("cartstore: commit: %w", err)
+	}
+
+	return s.Get(ctx, cartID)
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, cartID string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM carts WHERE cart_id = $1`, cartID)
+	if err != nil {
+		return fmt.Errorf("cartstore: delete cart: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FinalizeCheckout clears cartID and records msg in the outbox within a
+// single transaction, so a successful checkout can never leave an
+// authorized order without a matching outbox row (or vice versa) even if
+// the process crashes or RabbitMQ is unreachable right afterward. The
+// OutboxRelay is what actually gets msg onto the warehouse_orders queue.
+func (s *PostgresStore) FinalizeCheckout(ctx context.Context, cartID string, msg OutboxMessage) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("cartstore: begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM carts WHERE cart_id = $1`, cartID); err != nil {
+		return fmt.Errorf("cartstore: delete cart: %w", err)
+	}
+
+	headers, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return fmt.Errorf("cartstore: marshal outbox headers: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO outbox (id, aggregate_id, payload, headers, created_at) VALUES ($1, $2, $3::jsonb, $4::jsonb, $5)`,
+		msg.ID, msg.AggregateID, msg.Payload, headers, msg.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("cartstore: insert outbox row: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("cartstore: commit: %w", err)
+	}
+	return nil
+}
+
+// FetchUnpublished returns up to limit outbox rows that haven't been
+// published yet, oldest first.
+func (s *PostgresStore) FetchUnpublished(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, aggregate_id, payload, headers, created_at FROM outbox WHERE published_at IS NULL ORDER BY created_at LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cartstore: fetch unpublished outbox rows: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []OutboxMessage
+	for rows.Next() {
+		var msg OutboxMessage
+		var headers []byte
+		if err := rows.Scan(&msg.ID, &msg.AggregateID, &msg.Payload, &headers, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("cartstore: scan outbox row: %w", err)
+		}
+		if err := json.Unmarshal(headers, &msg.Headers); err != nil {
+			return nil, fmt.Errorf("cartstore: unmarshal outbox headers: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cartstore: fetch unpublished outbox rows: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkPublished stamps published_at on the outbox row id once the broker
+// has confirmed it.
+func (s *PostgresStore) MarkPublished(ctx context.Context, id string) error {
+	if _, err := s.pool.Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("cartstore: mark outbox row published: %w", err)
+	}
+	return nil
+}
+
+// CountUnpublished reports how many outbox rows are still waiting to be
+// relayed, for the outbox-lag metric.
+func (s *PostgresStore) CountUnpublished(ctx context.Context) (int, error) {
+	var count int
+	if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM outbox WHERE published_at IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("cartstore: count unpublished outbox rows: %w", err)
+	}
+	return count, nil
+}
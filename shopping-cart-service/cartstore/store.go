@@ -0,0 +1,78 @@
+// Package cartstore defines the persistence interface for shopping carts
+// and provides an in-memory implementation and a Postgres-backed one, so
+// package main can swap between them with the CART_STORE environment
+// variable without changing any handler or gRPC code.
+package cartstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get, AddItem, and Delete when the cart does
+// not exist.
+var ErrNotFound = errors.New("cart not found")
+
+// Item is a line item in a cart.
+type Item struct {
+	ProductID string
+	Quantity  int
+}
+
+// Cart is a shopping cart as persisted by a Store. Version is bumped on
+// every write and used by implementations that need optimistic
+// concurrency control.
+type Cart struct {
+	CartID     string
+	CustomerID string
+	Items      []Item
+	CreatedAt  time.Time
+	Version    int
+}
+
+// Store persists shopping carts. AddItem must be safe under concurrent
+// callers touching the same cart: implementations either serialize
+// access (MemoryStore) or detect and retry lost updates (PostgresStore).
+type Store interface {
+	Create(ctx context.Context, customerID string) (Cart, error)
+	Get(ctx context.Context, cartID string) (Cart, error)
+	AddItem(ctx context.Context, cartID, productID string, quantity int) (Cart, error)
+	Delete(ctx context.Context, cartID string) error
+}
+
+// OutboxMessage is a row in the outbox table: an order payload waiting to
+// be relayed to RabbitMQ. ID is used as the AMQP MessageId so downstream
+// consumers can dedupe if the relay publishes it more than once. Headers
+// carries the W3C trace context and X-Correlation-ID that would otherwise
+// only be set on the AMQP publish itself, so relayBatch can still stamp
+// them once the message reaches the broker through the outbox instead of
+// a direct publish.
+type OutboxMessage struct {
+	ID          string
+	AggregateID string
+	Payload     []byte
+	Headers     map[string]string
+	CreatedAt   time.Time
+}
+
+// OutboxStore is the subset of TransactionalStore the outbox relay and
+// the outbox-lag metric need: reading and acknowledging outbox rows
+// without caring how checkout produced them.
+type OutboxStore interface {
+	FetchUnpublished(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkPublished(ctx context.Context, id string) error
+	CountUnpublished(ctx context.Context) (int, error)
+}
+
+// TransactionalStore is implemented by Store backends that can finalize a
+// checkout atomically: clearing the cart and recording its resulting
+// order in the same database transaction, via a transactional outbox.
+// Only PostgresStore implements this; callers fall back to publishing
+// directly and deleting the cart as two separate steps when the store
+// doesn't.
+type TransactionalStore interface {
+	Store
+	OutboxStore
+	FinalizeCheckout(ctx context.Context, cartID string, msg OutboxMessage) error
+}
@@ -0,0 +1,61 @@
+// Command client is a minimal gRPC client that drives a full
+// create → add item → checkout flow against the shopping cart service's
+// gRPC port, useful for smoke-testing the ShoppingCartService API without
+// going through REST.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"shopping-cart-service/cartpb"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9090", "shopping-cart-service gRPC address")
+	customerID := flag.String("customer", "customer-123", "customer ID for the new cart")
+	productID := flag.String("product", "42", "product ID to add to the cart")
+	quantity := flag.Int("quantity", 2, "quantity to add")
+	creditCard := flag.String("card", "4111-1111-1111-1111", "credit card number for checkout")
+	flag.Parse()
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to dial %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	client := cartpb.NewShoppingCartServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cart, err := client.CreateCart(ctx, &cartpb.CreateCartRequest{CustomerId: *customerID})
+	if err != nil {
+		log.Fatalf("CreateCart failed: %v", err)
+	}
+	log.Printf("created cart %s for customer %s", cart.CartId, cart.CustomerId)
+
+	cart, err = client.AddItem(ctx, &cartpb.AddItemRequest{
+		CartId:    cart.CartId,
+		ProductId: *productID,
+		Quantity:  int32(*quantity),
+	})
+	if err != nil {
+		log.Fatalf("AddItem failed: %v", err)
+	}
+	log.Printf("cart %s now has %d line item(s)", cart.CartId, len(cart.Items))
+
+	resp, err := client.Checkout(ctx, &cartpb.CheckoutRequest{
+		CartId:           cart.CartId,
+		CreditCardNumber: *creditCard,
+	})
+	if err != nil {
+		log.Fatalf("Checkout failed: %v", err)
+	}
+	log.Printf("checkout complete: order %s (%s), total $%.2f", resp.OrderId, resp.AuthorizationStatus, resp.TotalAmount)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"shopping-cart-service/cartpb"
+	"shopping-cart-service/grpcserver"
+)
+
+// grpcPort is the port the gRPC server listens on, alongside the existing
+// Gin HTTP server on :8080.
+const grpcPort = ":9090"
+
+// cartStore adapts package main's cart operations to the grpcserver.Store
+// interface, so the gRPC and REST transports share the same cart store.
+type cartStore struct{}
+
+func (cartStore) CreateCart(ctx context.Context, customerID string) (grpcserver.Cart, error) {
+	cart, err := doCreateCart(ctx, customerID)
+	if err != nil {
+		return grpcserver.Cart{}, err
+	}
+	return toGRPCCart(cart), nil
+}
+
+func (cartStore) GetCart(ctx context.Context, cartID string) (grpcserver.Cart, bool, error) {
+	cart, ok, err := doGetCart(ctx, cartID)
+	if err != nil || !ok {
+		return grpcserver.Cart{}, ok, err
+	}
+	return toGRPCCart(cart), true, nil
+}
+
+func (cartStore) AddItem(ctx context.Context, cartID, productID string, quantity int) (grpcserver.Cart, bool, error) {
+	cart, ok, err := doAddItem(ctx, cartID, productID, quantity)
+	if err != nil || !ok {
+		return grpcserver.Cart{}, ok, err
+	}
+	return toGRPCCart(cart), true, nil
+}
+
+func (cartStore) Checkout(ctx context.Context, cartID, creditCardNumber string) (grpcserver.CheckoutResult, error) {
+	result, err := doCheckout(ctx, cartID, creditCardNumber)
+	if err != nil {
+		return grpcserver.CheckoutResult{}, err
+	}
+	return grpcserver.CheckoutResult{
+		OrderID:             result.OrderID,
+		AuthorizationStatus: result.AuthorizationStatus,
+		TransactionID:       result.TransactionID,
+		TotalAmount:         result.TotalAmount,
+	}, nil
+}
+
+func toGRPCCart(cart ShoppingCart) grpcserver.Cart {
+	items := make([]grpcserver.Item, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, grpcserver.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return grpcserver.Cart{
+		CartID:     cart.CartID,
+		CustomerID: cart.CustomerID,
+		Items:      items,
+		CreatedAt:  cart.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// startGRPCServer starts the gRPC server on grpcPort in the background. It
+// registers the ShoppingCartService alongside the standard gRPC health
+// service so orchestrators can probe readiness the same way they would for
+// HTTP's /health.
+func startGRPCServer() {
+	lis, err := net.Listen("tcp", grpcPort)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", grpcPort, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	cartpb.RegisterShoppingCartServiceServer(grpcServer, grpcserver.New(cartStore{}))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("cart.ShoppingCartService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	log.Printf("Shopping Cart Service gRPC server starting on %s", grpcPort)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server stopped: %v", err)
+		}
+	}()
+}
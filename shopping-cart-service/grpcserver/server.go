@@ -0,0 +1,116 @@
+// Package grpcserver implements the ShoppingCartService gRPC API defined
+// in proto/cart.proto, backed by the same cart storage and checkout logic
+// package main uses for its REST handlers.
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"shopping-cart-service/cartpb"
+)
+
+// Cart mirrors package main's ShoppingCart so this package doesn't import
+// package main (which would create an import cycle).
+type Cart struct {
+	CartID     string
+	CustomerID string
+	Items      []Item
+	CreatedAt  string
+}
+
+// Item mirrors package main's CartItem.
+type Item struct {
+	ProductID string
+	Quantity  int
+}
+
+// CheckoutResult is what a successful checkout reports back.
+type CheckoutResult struct {
+	OrderID             string
+	AuthorizationStatus string
+	TransactionID       string
+	TotalAmount         float64
+}
+
+// Store is the subset of cart operations the gRPC server needs. package
+// main satisfies this by wrapping its existing createCart/addItemToCart/
+// checkout logic.
+type Store interface {
+	CreateCart(ctx context.Context, customerID string) (Cart, error)
+	GetCart(ctx context.Context, cartID string) (Cart, bool, error)
+	AddItem(ctx context.Context, cartID, productID string, quantity int) (Cart, bool, error)
+	Checkout(ctx context.Context, cartID, creditCardNumber string) (CheckoutResult, error)
+}
+
+// Server implements cartpb.ShoppingCartServiceServer.
+type Server struct {
+	cartpb.UnimplementedShoppingCartServiceServer
+	store Store
+}
+
+// New returns a Server backed by store.
+func New(store Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) CreateCart(ctx context.Context, req *cartpb.CreateCartRequest) (*cartpb.Cart, error) {
+	if req.CustomerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "customer_id is required")
+	}
+	cart, err := s.store.CreateCart(ctx, req.CustomerId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create cart: %v", err)
+	}
+	return toProto(cart), nil
+}
+
+func (s *Server) GetCart(ctx context.Context, req *cartpb.GetCartRequest) (*cartpb.Cart, error) {
+	cart, ok, err := s.store.GetCart(ctx, req.CartId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get cart: %v", err)
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "cart %s not found", req.CartId)
+	}
+	return toProto(cart), nil
+}
+
+func (s *Server) AddItem(ctx context.Context, req *cartpb.AddItemRequest) (*cartpb.Cart, error) {
+	cart, ok, err := s.store.AddItem(ctx, req.CartId, req.ProductId, int(req.Quantity))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "add item: %v", err)
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "cart %s not found", req.CartId)
+	}
+	return toProto(cart), nil
+}
+
+func (s *Server) Checkout(ctx context.Context, req *cartpb.CheckoutRequest) (*cartpb.CheckoutResponse, error) {
+	result, err := s.store.Checkout(ctx, req.CartId, req.CreditCardNumber)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &cartpb.CheckoutResponse{
+		OrderId:             result.OrderID,
+		AuthorizationStatus: result.AuthorizationStatus,
+		TransactionId:       result.TransactionID,
+		TotalAmount:         result.TotalAmount,
+	}, nil
+}
+
+func toProto(c Cart) *cartpb.Cart {
+	items := make([]*cartpb.CartItem, 0, len(c.Items))
+	for _, item := range c.Items {
+		items = append(items, &cartpb.CartItem{ProductId: item.ProductID, Quantity: int32(item.Quantity)})
+	}
+	return &cartpb.Cart{
+		CartId:     c.CartID,
+		CustomerId: c.CustomerID,
+		Items:      items,
+		CreatedAt:  c.CreatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var httpServerDuration metric.Float64Histogram
+
+// initHTTPMetrics creates the http_server_requests_seconds histogram. Call
+// once from main after telemetry.Init.
+func initHTTPMetrics() {
+	var err error
+	httpServerDuration, err = meter.Float64Histogram(
+		"http_server_requests_seconds",
+		metric.WithDescription("Duration of HTTP requests served by this instance"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create http_server_requests_seconds histogram: %v", err)
+	}
+}
+
+// httpMetricsMiddleware records http_server_requests_seconds for every
+// request, labeled by route, method, and response status.
+func httpMetricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	httpServerDuration.Record(c.Request.Context(), time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("method", c.Request.Method),
+		attribute.String("route", c.FullPath()),
+		attribute.String("status", strconv.Itoa(c.Writer.Status())),
+	))
+}
@@ -0,0 +1,177 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// State is one of the three circuit breaker states.
+type State int
+
+const (
+	// Closed allows requests through and tracks their outcome.
+	Closed State = iota
+	// Open rejects requests immediately until OpenDuration elapses.
+	Open
+	// HalfOpen allows a single probe request to decide whether to
+	// return to Closed or back off to Open.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker tracks request outcomes for a single endpoint over a
+// rolling window and trips from Closed to Open once both MinRequests and
+// FailureRatio are exceeded within that window.
+type circuitBreaker struct {
+	cfg Config
+
+	mu            sync.Mutex
+	state         State
+	windowStart   time.Time
+	requests      int
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+}
+
+func newCircuitBreaker(cfg Config) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg, state: Closed, windowStart: time.Now()}
+}
+
+// Allow reports whether a request may proceed, transitioning Open->HalfOpen
+// once OpenDuration has elapsed.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Closed:
+		return true
+	case Open:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return false
+		}
+		b.state = HalfOpen
+		b.probeInFlight = true
+		return true
+	case HalfOpen:
+		// Only the request that triggered the HalfOpen transition is let
+		// through; concurrent callers are rejected until it resolves.
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	default:
+		return false
+	}
+}
+
+// RecordSuccess reports a successful call against the current window.
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case HalfOpen:
+		b.reset()
+	case Closed:
+		b.rollWindow()
+		b.requests++
+	}
+}
+
+// RecordFailure reports a failed call and trips the breaker when the
+// rolling window's failure ratio and minimum request count are exceeded.
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case HalfOpen:
+		b.trip()
+	case Closed:
+		b.rollWindow()
+		b.requests++
+		b.failures++
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRatio {
+			b.trip()
+		}
+	}
+}
+
+// State returns the breaker's current state for health reporting.
+func (b *circuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = Open
+	b.openedAt = time.Now()
+	b.probeInFlight = false
+}
+
+func (b *circuitBreaker) reset() {
+	b.state = Closed
+	b.requests = 0
+	b.failures = 0
+	b.windowStart = time.Now()
+	b.probeInFlight = false
+}
+
+// rollWindow clears accumulated counts once the rolling window has elapsed.
+func (b *circuitBreaker) rollWindow() {
+	if time.Since(b.windowStart) >= b.cfg.Window {
+		b.requests = 0
+		b.failures = 0
+		b.windowStart = time.Now()
+	}
+}
+
+// breakerRegistry lazily creates one circuitBreaker per endpoint key.
+type breakerRegistry struct {
+	cfg Config
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerRegistry(cfg Config) *breakerRegistry {
+	return &breakerRegistry{cfg: cfg, breakers: make(map[string]*circuitBreaker)}
+}
+
+func (r *breakerRegistry) get(key string) *circuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[key]
+	if !ok {
+		b = newCircuitBreaker(r.cfg)
+		r.breakers[key] = b
+	}
+	return b
+}
+
+func (r *breakerRegistry) peek(key string) (State, bool) {
+	r.mu.Lock()
+	b, ok := r.breakers[key]
+	r.mu.Unlock()
+	if !ok {
+		return Closed, false
+	}
+	return b.State(), true
+}
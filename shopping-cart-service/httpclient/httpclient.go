@@ -0,0 +1,215 @@
+// Package httpclient provides a resilient HTTP client for calling downstream
+// services (e.g. the Credit Card Authorizer) that may fail intermittently.
+// It layers a bounded, jittered exponential-backoff retry and a per-endpoint
+// circuit breaker on top of the standard library's http.Client.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// ErrCircuitOpen is returned by Do when the circuit breaker for the
+// request's endpoint is open and the call is short-circuited.
+var ErrCircuitOpen = errors.New("httpclient: circuit breaker is open")
+
+// Doer is satisfied by anything that can execute an *http.Request, which
+// lets callers swap in the resilient Client (or a test double) wherever
+// they'd otherwise use *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config controls retry and circuit breaker behavior. Zero-valued fields
+// fall back to sane defaults in New.
+type Config struct {
+	// MaxRetries is the number of additional attempts after the first one.
+	MaxRetries int
+	// BaseDelay is the starting backoff delay; it doubles on each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay regardless of attempt count.
+	MaxDelay time.Duration
+	// Timeout bounds a single attempt, applied via context.Context.
+	Timeout time.Duration
+
+	// FailureRatio is the fraction of failed requests (0-1) within a
+	// rolling window that trips the breaker from Closed to Open.
+	FailureRatio float64
+	// MinRequests is the minimum number of requests in the window before
+	// FailureRatio is evaluated, so a handful of early failures can't trip
+	// the breaker on their own.
+	MinRequests int
+	// Window is the length of the rolling window used to count failures.
+	Window time.Duration
+	// OpenDuration is how long the breaker stays Open before allowing a
+	// single Half-Open probe request through.
+	OpenDuration time.Duration
+}
+
+// defaults fills in zero-valued Config fields.
+func (c Config) defaults() Config {
+	if c.MaxRetries == 0 {
+		c.MaxRetries = 3
+	}
+	if c.BaseDelay == 0 {
+		c.BaseDelay = 100 * time.Millisecond
+	}
+	if c.MaxDelay == 0 {
+		c.MaxDelay = 2 * time.Second
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.FailureRatio == 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.MinRequests == 0 {
+		c.MinRequests = 5
+	}
+	if c.Window == 0 {
+		c.Window = 10 * time.Second
+	}
+	if c.OpenDuration == 0 {
+		c.OpenDuration = 5 * time.Second
+	}
+	return c
+}
+
+// Client wraps an underlying Doer with retry and per-endpoint circuit
+// breaker behavior. The zero value is not usable; construct with New.
+type Client struct {
+	cfg      Config
+	inner    Doer
+	breakers *breakerRegistry
+}
+
+// New returns a Client configured by cfg. Endpoints are keyed by
+// request URL host+path, so a breaker trip on one route doesn't affect
+// another route served by the same host.
+func New(cfg Config) *Client {
+	cfg = cfg.defaults()
+	return &Client{
+		cfg: cfg,
+		// otelhttp.NewTransport propagates the W3C traceparent header from
+		// the request's context and records a client span per attempt.
+		inner:    &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		breakers: newBreakerRegistry(cfg),
+	}
+}
+
+// Do executes req, retrying on 5xx/429 responses and connection errors with
+// jittered exponential backoff, and short-circuiting via a per-endpoint
+// circuit breaker. The response body of any returned *http.Response is the
+// caller's to close.
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	breaker := c.breakers.get(endpointKey(req))
+
+	if !breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, backoff(c.cfg, attempt)); err != nil {
+				return nil, err
+			}
+			// A retry after the first attempt re-checks the breaker in case
+			// an earlier attempt's failure just tripped it open.
+			if !breaker.Allow() {
+				return nil, ErrCircuitOpen
+			}
+		}
+
+		resp, err := c.attempt(ctx, req)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			breaker.RecordSuccess()
+			return resp, nil
+		}
+
+		if err == nil {
+			// Retryable status: drain and close so the connection can be
+			// reused, then decide whether to retry.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			lastErr = &StatusError{StatusCode: resp.StatusCode}
+		} else {
+			lastErr = err
+		}
+
+		breaker.RecordFailure()
+		if attempt == c.cfg.MaxRetries {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attempt performs a single HTTP call bounded by cfg.Timeout.
+func (c *Client) attempt(ctx context.Context, req *http.Request) (*http.Response, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	r := req.Clone(attemptCtx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	return c.inner.Do(r)
+}
+
+// BreakerState reports the current state of the breaker for host+path,
+// for surfacing in a health check. It returns false if no requests have
+// been made to that endpoint yet.
+func (c *Client) BreakerState(method, host, path string) (State, bool) {
+	return c.breakers.peek(method + " " + host + path)
+}
+
+// StatusError wraps a non-2xx response that exhausted retries.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return http.StatusText(e.StatusCode)
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func backoff(cfg Config, attempt int) time.Duration {
+	d := cfg.BaseDelay << uint(attempt-1)
+	if d > cfg.MaxDelay || d <= 0 {
+		d = cfg.MaxDelay
+	}
+	// Full jitter: pick uniformly in [0, d) so retrying callers don't
+	// synchronize on the same backoff schedule.
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func endpointKey(req *http.Request) string {
+	return req.Method + " " + req.URL.Host + req.URL.Path
+}
@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig returns a Config tuned for fast, deterministic tests: no
+// inter-attempt sleep worth waiting on and a breaker that trips on the
+// third failure.
+func testConfig() Config {
+	return Config{
+		MaxRetries:   3,
+		BaseDelay:    time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Timeout:      time.Second,
+		FailureRatio: 0.5,
+		MinRequests:  3,
+		Window:       time.Minute,
+		OpenDuration: time.Minute,
+	}
+}
+
+func newRequest(t *testing.T, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest: %v", err)
+	}
+	return req
+}
+
+func TestClient_Do_RetriesUntilSuccess(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := New(testConfig())
+	resp, err := client.Do(context.Background(), newRequest(t, server.URL))
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("server saw %d attempts, want 3 (2 failures + 1 success)", got)
+	}
+}
+
+func TestClient_Do_ExhaustsRetriesOnPersistentFailure(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.MinRequests = 1000 // keep the breaker out of this test
+	client := New(cfg)
+
+	_, err := client.Do(context.Background(), newRequest(t, server.URL))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want *StatusError{503}", err)
+	}
+	if got, want := atomic.LoadInt32(&attempts), int32(cfg.MaxRetries+1); got != want {
+		t.Fatalf("server saw %d attempts, want %d (first attempt + %d retries)", got, want, cfg.MaxRetries)
+	}
+}
+
+func TestClient_Do_BreakerOpensAfterConsecutiveFailuresAndShortCircuits(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.MaxRetries = 0 // one request per Do call, so each call is one breaker outcome
+	cfg.MinRequests = 3
+	cfg.FailureRatio = 0.5
+	client := New(cfg)
+
+	req := newRequest(t, server.URL)
+	for i := 0; i < cfg.MinRequests; i++ {
+		if _, err := client.Do(context.Background(), req); err == nil {
+			t.Fatalf("request %d: expected failure against a 503-only server", i)
+		}
+	}
+
+	state, ok := client.BreakerState(req.Method, req.URL.Host, req.URL.Path)
+	if !ok || state != Open {
+		t.Fatalf("breaker state = %v (ok=%v), want Open after %d consecutive failures", state, ok, cfg.MinRequests)
+	}
+
+	seenBeforeShortCircuit := atomic.LoadInt32(&attempts)
+	if _, err := client.Do(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != seenBeforeShortCircuit {
+		t.Fatalf("server saw %d more request(s) after the breaker opened, want 0 (short-circuited)", got-seenBeforeShortCircuit)
+	}
+}
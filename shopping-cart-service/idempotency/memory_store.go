@@ -0,0 +1,68 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store backed by sync.Map, suitable for a
+// single-replica deployment. Entries are expired lazily on Load and swept
+// periodically by a background goroutine so keys that are never re-read
+// still get reclaimed.
+type MemoryStore struct {
+	entries sync.Map // key -> Entry
+	locks   sync.Map // key -> *sync.Mutex
+}
+
+// NewMemoryStore returns a MemoryStore that sweeps expired entries every
+// cleanupInterval. Callers that don't care about proactive cleanup (lazy
+// expiry on Load is always applied) can pass 0 to disable the sweeper.
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{}
+	if cleanupInterval > 0 {
+		go s.sweepLoop(cleanupInterval)
+	}
+	return s
+}
+
+// Lock returns an unlock function for key's per-key mutex, creating it on
+// first use. It never fails.
+func (s *MemoryStore) Lock(key string) (func(), error) {
+	v, _ := s.locks.LoadOrStore(key, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock, nil
+}
+
+// Load returns key's cached entry if present and not expired.
+func (s *MemoryStore) Load(key string) (Entry, bool) {
+	v, ok := s.entries.Load(key)
+	if !ok {
+		return Entry{}, false
+	}
+	entry := v.(Entry)
+	if time.Now().After(entry.ExpiresAt) {
+		s.entries.Delete(key)
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Save stores entry for key.
+func (s *MemoryStore) Save(key string, entry Entry) {
+	s.entries.Store(key, entry)
+}
+
+func (s *MemoryStore) sweepLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		s.entries.Range(func(key, value interface{}) bool {
+			if now.After(value.(Entry).ExpiresAt) {
+				s.entries.Delete(key)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderKey is the request header clients set to make a POST idempotent.
+const HeaderKey = "Idempotency-Key"
+
+// DefaultTTL is how long a cached response is replayed before it expires.
+const DefaultTTL = 24 * time.Hour
+
+// Middleware returns Gin middleware that makes POST handlers idempotent.
+// Requests without an Idempotency-Key header pass through unchanged.
+// Requests with the header are deduplicated by (key, method, path,
+// sha256(body)):
+//
+//   - A first request for a key runs the handler normally; on success
+//     (2xx) its status and body are cached until ttl elapses.
+//   - A concurrent or later request for the same key blocks on a per-key
+//     lock, then replays the cached response verbatim once available.
+//   - A request reusing a key with a different body is rejected with 422,
+//     since the client is misusing the key rather than retrying.
+func Middleware(store Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		bodyHash := sha256Hex(body)
+
+		cacheKey := key + "|" + c.Request.Method + "|" + c.Request.URL.Path
+
+		unlock, err := store.Lock(cacheKey)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency store unavailable"})
+			return
+		}
+		defer unlock()
+
+		if entry, ok := store.Load(cacheKey); ok {
+			if entry.BodyHash != bodyHash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "Idempotency-Key was already used with a different request body",
+				})
+				return
+			}
+			c.Data(entry.StatusCode, "application/json", entry.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, buf: &bytes.Buffer{}, status: http.StatusOK}
+		c.Writer = recorder
+
+		c.Next()
+
+		if !c.IsAborted() && recorder.status >= 200 && recorder.status < 300 {
+			store.Save(cacheKey, Entry{
+				BodyHash:   bodyHash,
+				StatusCode: recorder.status,
+				Body:       recorder.buf.Bytes(),
+				ExpiresAt:  time.Now().Add(ttl),
+			})
+		}
+	}
+}
+
+// responseRecorder captures the response body alongside writing it through,
+// so a successful response can be cached verbatim for replay.
+type responseRecorder struct {
+	gin.ResponseWriter
+	buf    *bytes.Buffer
+	status int
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.buf.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.buf.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(store Store, ttl time.Duration, handlerCalls *int32) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(store, ttl))
+	r.POST("/checkout", func(c *gin.Context) {
+		n := atomic.AddInt32(handlerCalls, 1)
+		c.JSON(http.StatusOK, gin.H{"order_id": strconv.Itoa(int(n))})
+	})
+	return r
+}
+
+func doPost(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/checkout", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ConcurrentReplay_HandlerRunsOnce(t *testing.T) {
+	var handlerCalls int32
+	r := newTestRouter(NewMemoryStore(0), DefaultTTL, &handlerCalls)
+
+	const n = 10
+	var wg sync.WaitGroup
+	bodies := make([]string, n)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			bodies[i] = doPost(r, "order-1", `{"cart_id":"abc"}`).Body.String()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&handlerCalls); got != 1 {
+		t.Fatalf("handler ran %d times, want 1 (all requests should share one cached response)", got)
+	}
+	for i, body := range bodies {
+		if body != bodies[0] {
+			t.Fatalf("response %d = %q, want all responses identical to %q", i, body, bodies[0])
+		}
+	}
+}
+
+func TestMiddleware_DifferentBodySameKey_Rejected(t *testing.T) {
+	var handlerCalls int32
+	r := newTestRouter(NewMemoryStore(0), DefaultTTL, &handlerCalls)
+
+	first := doPost(r, "order-2", `{"cart_id":"abc"}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", first.Code)
+	}
+
+	second := doPost(r, "order-2", `{"cart_id":"different"}`)
+	if second.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("second request (same key, different body) status = %d, want 422", second.Code)
+	}
+
+	if got := atomic.LoadInt32(&handlerCalls); got != 1 {
+		t.Fatalf("handler ran %d times, want 1 (rejected request must not reach the handler)", got)
+	}
+}
+
+func TestMiddleware_CachedEntryExpires(t *testing.T) {
+	var handlerCalls int32
+	r := newTestRouter(NewMemoryStore(0), 10*time.Millisecond, &handlerCalls)
+
+	first := doPost(r, "order-3", `{"cart_id":"abc"}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", first.Code)
+	}
+
+	replay := doPost(r, "order-3", `{"cart_id":"abc"}`)
+	if replay.Body.String() != first.Body.String() {
+		t.Fatalf("replay before expiry = %q, want cached response %q", replay.Body.String(), first.Body.String())
+	}
+	if got := atomic.LoadInt32(&handlerCalls); got != 1 {
+		t.Fatalf("handler ran %d times before expiry, want 1", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	afterExpiry := doPost(r, "order-3", `{"cart_id":"abc"}`)
+	if afterExpiry.Code != http.StatusOK {
+		t.Fatalf("request after expiry status = %d, want 200", afterExpiry.Code)
+	}
+	if got := atomic.LoadInt32(&handlerCalls); got != 2 {
+		t.Fatalf("handler ran %d times, want 2 (expired entry must not be replayed)", got)
+	}
+}
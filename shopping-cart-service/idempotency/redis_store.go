@@ -0,0 +1,39 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// errRedisStoreUnimplemented is returned by every RedisStore method until a
+// real client is wired in.
+var errRedisStoreUnimplemented = errors.New("idempotency: RedisStore is not implemented yet")
+
+// RedisStore is a placeholder Redis-backed Store for when the idempotency
+// cache needs to survive a restart or be shared across replicas (the
+// in-memory MemoryStore is per-process). Swap in a real client (e.g.
+// github.com/redis/go-redis/v9) and implement Lock via SET NX + a Lua
+// unlock script, and Load/Save via GET/SETEX, when that need arrives.
+type RedisStore struct {
+	addr string
+}
+
+// NewRedisStore returns a RedisStore pointed at addr. It is not yet
+// functional; every method returns errRedisStoreUnimplemented.
+func NewRedisStore(addr string) *RedisStore {
+	return &RedisStore{addr: addr}
+}
+
+func (s *RedisStore) Lock(key string) (func(), error) {
+	// TODO: implement via SET key NX PX <lease> and release via a
+	// compare-and-delete Lua script once a redis client is added.
+	return nil, errRedisStoreUnimplemented
+}
+
+func (s *RedisStore) Load(key string) (Entry, bool) {
+	return Entry{}, false
+}
+
+func (s *RedisStore) Save(key string, entry Entry) {
+	_ = time.Now() // placeholder until TTL is set via SETEX
+}
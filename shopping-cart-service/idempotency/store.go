@@ -0,0 +1,32 @@
+// Package idempotency provides an Idempotency-Key middleware for Gin so
+// that retried POSTs (e.g. a client retrying a timed-out checkout) don't
+// double-charge a card or double-publish a warehouse order.
+package idempotency
+
+import "time"
+
+// Entry is a cached response for a previously completed idempotent request.
+type Entry struct {
+	// BodyHash is the sha256 of the request body that produced this
+	// response, used to detect a key reused with a different body.
+	BodyHash string
+	// StatusCode and Body are the response to replay verbatim.
+	StatusCode int
+	Body       []byte
+	ExpiresAt  time.Time
+}
+
+// Store persists idempotent responses keyed by (Idempotency-Key, method,
+// path). Implementations must make Lock/Load/Save safe for concurrent use
+// across goroutines (and, for a shared backend, across processes).
+type Store interface {
+	// Lock serializes concurrent requests carrying the same key so only
+	// one executes the handler while the others wait for its result. It
+	// returns an unlock function the caller must call exactly once, or an
+	// error if the lock couldn't be acquired (e.g. the backend is down).
+	Lock(key string) (func(), error)
+	// Load returns the cached entry for key, if any and not expired.
+	Load(key string) (Entry, bool)
+	// Save stores entry for key, to be replayed until entry.ExpiresAt.
+	Save(key string, entry Entry)
+}
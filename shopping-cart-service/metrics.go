@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"shopping-cart-service/cartstore"
+)
+
+// meter is the service's OpenTelemetry meter, used to create the
+// instruments below. telemetry.Init must run before these are registered
+// so they attach to the configured MeterProvider rather than the no-op
+// default.
+var meter = otel.Meter("shopping-cart-service")
+
+var (
+	checkoutTotal       metric.Int64Counter
+	ccaAuthDuration     metric.Float64Histogram
+	rabbitmqPublishFail metric.Int64Counter
+)
+
+// initMetrics creates the service's custom instruments. Call once from
+// main after telemetry.Init.
+func initMetrics() {
+	var err error
+
+	checkoutTotal, err = meter.Int64Counter(
+		"cart_checkout_total",
+		metric.WithDescription("Number of checkout attempts by outcome"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create cart_checkout_total counter: %v", err)
+	}
+
+	ccaAuthDuration, err = meter.Float64Histogram(
+		"cca_authorization_duration_seconds",
+		metric.WithDescription("Duration of calls to the Credit Card Authorizer"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create cca_authorization_duration_seconds histogram: %v", err)
+	}
+
+	rabbitmqPublishFail, err = meter.Int64Counter(
+		"rabbitmq_publish_failures_total",
+		metric.WithDescription("Number of failed RabbitMQ publishes"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create rabbitmq_publish_failures_total counter: %v", err)
+	}
+}
+
+// recordCheckout increments cart_checkout_total with the given outcome
+// (e.g. "authorized", "declined", "error").
+func recordCheckout(ctx context.Context, outcome string) {
+	checkoutTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+// registerOutboxLagGauge wires an observable gauge reporting how many
+// outbox rows are still waiting to be relayed to RabbitMQ. Only called
+// when the cart store is Postgres-backed.
+func registerOutboxLagGauge(store cartstore.OutboxStore) {
+	lagGauge, err := meter.Int64ObservableGauge(
+		"cart_outbox_lag",
+		metric.WithDescription("Number of outbox rows not yet published to RabbitMQ"),
+	)
+	if err != nil {
+		log.Fatalf("failed to create cart_outbox_lag gauge: %v", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		lag, err := store.CountUnpublished(ctx)
+		if err != nil {
+			return err
+		}
+		o.ObserveInt64(lagGauge, int64(lag))
+		return nil
+	}, lagGauge)
+	if err != nil {
+		log.Fatalf("failed to register cart_outbox_lag callback: %v", err)
+	}
+}
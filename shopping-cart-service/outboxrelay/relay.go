@@ -0,0 +1,131 @@
+// Package outboxrelay relays rows written to the Postgres outbox table by
+// cartstore.PostgresStore.FinalizeCheckout onto the warehouse_orders
+// queue, so checkout's cart-clearing and order-publishing steps behave as
+// one atomic unit even though RabbitMQ itself isn't part of the database
+// transaction.
+package outboxrelay
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"shopping-cart-service/cartstore"
+)
+
+const (
+	batchSize      = 20
+	defaultPoll    = time.Second
+	defaultBackoff = 30 * time.Second
+)
+
+// Relay polls store for unpublished outbox rows and publishes them to
+// queueName over channel, using publisher confirms to know when it's safe
+// to mark a row published.
+type Relay struct {
+	store     cartstore.OutboxStore
+	channel   *amqp.Channel
+	queueName string
+
+	pollEvery  time.Duration
+	maxBackoff time.Duration
+}
+
+// New returns a Relay. channel must not be shared with other publishers:
+// Run puts it into confirm mode and consumes its NotifyPublish channel.
+func New(store cartstore.OutboxStore, channel *amqp.Channel, queueName string) *Relay {
+	return &Relay{
+		store:      store,
+		channel:    channel,
+		queueName:  queueName,
+		pollEvery:  defaultPoll,
+		maxBackoff: defaultBackoff,
+	}
+}
+
+// Run polls until ctx is canceled. On a broker or store error it backs off
+// exponentially (capped at maxBackoff) before retrying, and resets to the
+// normal poll interval as soon as a batch succeeds.
+func (r *Relay) Run(ctx context.Context) {
+	if err := r.channel.Confirm(false); err != nil {
+		log.Fatalf("outbox relay: failed to enable publisher confirms: %v", err)
+	}
+	confirms := r.channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	interval := r.pollEvery
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			n, err := r.relayBatch(ctx, confirms)
+			if err != nil {
+				log.Printf("outbox relay: %v", err)
+				interval = nextBackoff(interval, r.maxBackoff)
+			} else {
+				interval = r.pollEvery
+				if n > 0 {
+					log.Printf("outbox relay: published %d order(s)", n)
+				}
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// relayBatch publishes up to batchSize unpublished rows and marks each one
+// published as soon as the broker confirms it. It stops and returns an
+// error at the first failure, leaving the remaining rows for the next
+// poll.
+func (r *Relay) relayBatch(ctx context.Context, confirms <-chan amqp.Confirmation) (int, error) {
+	messages, err := r.store.FetchUnpublished(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("fetch unpublished: %w", err)
+	}
+
+	for i, msg := range messages {
+		headers := amqp.Table{}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+
+		err := r.channel.PublishWithContext(ctx, "", r.queueName, false, false, amqp.Publishing{
+			MessageId:    msg.ID,
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         msg.Payload,
+			Headers:      headers,
+		})
+		if err != nil {
+			return i, fmt.Errorf("publish outbox row %s: %w", msg.ID, err)
+		}
+
+		confirm, ok := <-confirms
+		if !ok {
+			return i, fmt.Errorf("publisher confirm channel closed while waiting on outbox row %s", msg.ID)
+		}
+		if !confirm.Ack {
+			return i, fmt.Errorf("broker nacked outbox row %s", msg.ID)
+		}
+
+		if err := r.store.MarkPublished(ctx, msg.ID); err != nil {
+			return i, fmt.Errorf("mark outbox row %s published: %w", msg.ID, err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next > max {
+		return max
+	}
+	return next
+}
@@ -0,0 +1,31 @@
+package telemetry
+
+import amqp "github.com/rabbitmq/amqp091-go"
+
+// AMQPHeaderCarrier adapts amqp.Table to propagation.TextMapCarrier so a
+// trace context can be injected into amqp.Publishing.Headers before
+// publishing, and extracted again on the consumer side. Values are stored
+// as plain strings since that's all the W3C traceparent/baggage headers
+// need.
+type AMQPHeaderCarrier amqp.Table
+
+func (c AMQPHeaderCarrier) Get(key string) string {
+	v, ok := c[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func (c AMQPHeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c AMQPHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
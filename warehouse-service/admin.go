@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"warehouse-service/broker"
+)
+
+// adminRouter serves a small set of operator-facing endpoints separate
+// from the order-processing pipeline: order throughput, how many
+// messages have piled up in the dead-letter destination, whether the
+// payment authorizer's circuit breaker is tripped, and Prometheus
+// scraping of the counters/histograms/gauges registered in metrics.go.
+func adminRouter(mq broker.Broker) *gin.Engine {
+	router := gin.Default()
+	router.GET("/admin/stats", statsHandler(mq))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	return router
+}
+
+func statsHandler(mq broker.Broker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats := gin.H{
+			"total_orders":          atomic.LoadInt64(&totalOrders),
+			"payment_breaker_state": payments.BreakerState(),
+		}
+
+		depth, err := mq.DeadLetterDepth(c.Request.Context())
+		if err != nil {
+			stats["dlq_depth_error"] = err.Error()
+		} else {
+			stats["dlq_depth"] = depth
+		}
+
+		c.JSON(http.StatusOK, stats)
+	}
+}
@@ -0,0 +1,62 @@
+// Package broker abstracts the queue the warehouse service consumes
+// orders from, so main.go's worker fan-out doesn't need to know whether
+// it's talking to RabbitMQ or NATS JetStream. Each Broker is constructed
+// already bound to the queue/stream it serves (see RabbitMQConfig and
+// NATSConfig), which is what lets Consume take no queue argument and a
+// deployment switch backends via BROKER_TYPE alone.
+package broker
+
+import "context"
+
+// Message is a single delivery from a Broker, abstracted over the
+// underlying transport (an amqp091-go Delivery or a NATS JetStream Msg).
+type Message interface {
+	// Body returns the raw message payload.
+	Body() []byte
+	// Headers returns the message's string headers, such as the W3C
+	// traceparent/baggage pair the shopping-cart service injects at
+	// checkout.
+	Headers() map[string]string
+	// Ack acknowledges successful processing.
+	Ack() error
+	// Nak signals that processing failed, redelivering it immediately.
+	// Processing code that wants backoff between attempts should use
+	// Retry instead.
+	Nak() error
+	// RetryCount reports how many times this message has already been
+	// delivered before this one (0 on its first delivery).
+	RetryCount() int
+	// Retry schedules this message for redelivery after a backoff delay
+	// appropriate for attempt (the 1-indexed number of times it's now
+	// been tried), stamps cause for visibility, and acknowledges the
+	// current delivery.
+	Retry(ctx context.Context, attempt int, cause string) error
+	// DeadLetter moves this message to the broker's terminal
+	// dead-letter destination, stamping cause, and acknowledges the
+	// current delivery. Use once a message has exhausted its retry
+	// budget.
+	DeadLetter(ctx context.Context, cause string) error
+	// Declined moves this message to the broker's payment-declined
+	// destination, stamping reason, and acknowledges the current
+	// delivery. Use when a downstream service explicitly rejects the
+	// order (e.g. the payment authorizer declines the card) rather than
+	// failing transiently.
+	Declined(ctx context.Context, reason string) error
+}
+
+// Broker is a message queue a warehouse worker pool can consume orders
+// from and, in principle, publish them back to.
+type Broker interface {
+	// Consume starts delivering messages and returns the channel they
+	// arrive on. The channel closes once ctx is canceled and any
+	// already-buffered deliveries have been forwarded.
+	Consume(ctx context.Context) (<-chan Message, error)
+	// Publish sends body to the broker's configured destination with the
+	// given headers attached.
+	Publish(ctx context.Context, body []byte, headers map[string]string) error
+	// DeadLetterDepth reports how many messages are currently parked in
+	// the dead-letter destination, for the admin stats endpoint.
+	DeadLetterDepth(ctx context.Context) (int, error)
+	// Close releases the broker's underlying connection.
+	Close() error
+}
@@ -0,0 +1,269 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSConfig configures a NATSBroker.
+type NATSConfig struct {
+	URL     string
+	Stream  string // e.g. "WAREHOUSE_ORDERS"
+	Subject string // e.g. "warehouse.orders.*"
+	Durable string // durable consumer name, e.g. "warehouse-workers"
+	// MaxAckPending bounds how many unacked messages the consumer will
+	// have in flight at once, NATS's analogue of RabbitMQ's prefetch
+	// count.
+	MaxAckPending int
+	// RetryDelays is the backoff schedule for Message.Retry, used the
+	// same way RabbitMQConfig.RetryDelays is: attempt N waits
+	// RetryDelays[N-1], clamped to the last entry beyond that.
+	RetryDelays []time.Duration
+	// DLQStream and DLQSubject name a second JetStream stream messages
+	// are moved to once they exhaust their retry budget.
+	DLQStream  string
+	DLQSubject string
+	// DeclinedStream and DeclinedSubject name a third JetStream stream
+	// messages are moved to when a downstream service (e.g. payment
+	// authorization) explicitly rejects them.
+	DeclinedStream  string
+	DeclinedSubject string
+}
+
+// NATSBroker is a Broker backed by a NATS JetStream durable pull
+// consumer, bound to a single stream/subject. Retries use JetStream's
+// native NakWithDelay rather than a RabbitMQ-style delay-queue/DLX
+// topology; dead-lettered and declined messages are each republished
+// onto their own separate stream.
+type NATSBroker struct {
+	nc      *nats.Conn
+	js      jetstream.JetStream
+	sub     jetstream.Consumer
+	subject string
+
+	retryDelays     []time.Duration
+	dlqStream       string
+	dlqSubject      string
+	declinedStream  string
+	declinedSubject string
+
+	consCtx jetstream.ConsumeContext
+}
+
+// NewNATSBroker connects to cfg.URL, creates (or reuses) cfg.Stream bound
+// to cfg.Subject, creates (or reuses) a durable pull consumer on it with
+// cfg.MaxAckPending in-flight messages allowed, and creates (or reuses)
+// cfg.DLQStream for dead-lettered messages and cfg.DeclinedStream for
+// declined ones.
+func NewNATSBroker(ctx context.Context, cfg NATSConfig) (*NATSBroker, error) {
+	nc, err := nats.Connect(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("broker: connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("broker: create jetstream context: %w", err)
+	}
+
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     cfg.Stream,
+		Subjects: []string{cfg.Subject},
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("broker: create/update stream %s: %w", cfg.Stream, err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       cfg.Durable,
+		FilterSubject: cfg.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxAckPending: cfg.MaxAckPending,
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("broker: create/update consumer %s: %w", cfg.Durable, err)
+	}
+
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     cfg.DLQStream,
+		Subjects: []string{cfg.DLQSubject},
+	}); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("broker: create/update DLQ stream %s: %w", cfg.DLQStream, err)
+	}
+
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     cfg.DeclinedStream,
+		Subjects: []string{cfg.DeclinedSubject},
+	}); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("broker: create/update declined stream %s: %w", cfg.DeclinedStream, err)
+	}
+
+	return &NATSBroker{
+		nc:              nc,
+		js:              js,
+		sub:             consumer,
+		subject:         cfg.Subject,
+		retryDelays:     cfg.RetryDelays,
+		dlqStream:       cfg.DLQStream,
+		dlqSubject:      cfg.DLQSubject,
+		declinedStream:  cfg.DeclinedStream,
+		declinedSubject: cfg.DeclinedSubject,
+	}, nil
+}
+
+func (b *NATSBroker) Consume(ctx context.Context) (<-chan Message, error) {
+	out := make(chan Message)
+
+	consCtx, err := b.sub.Consume(func(msg jetstream.Msg) {
+		out <- &natsMessage{msg: msg, broker: b}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("broker: start consuming %s: %w", b.subject, err)
+	}
+	b.consCtx = consCtx
+
+	go func() {
+		<-ctx.Done()
+		consCtx.Stop()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+func (b *NATSBroker) Publish(ctx context.Context, body []byte, headers map[string]string) error {
+	msg := nats.NewMsg(b.subject)
+	msg.Data = body
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+
+	_, err := b.js.PublishMsg(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("broker: publish to %s: %w", b.subject, err)
+	}
+	return nil
+}
+
+// retryDelay returns the backoff delay for attempt (1-indexed), clamped
+// to the last configured tier.
+func (b *NATSBroker) retryDelay(attempt int) time.Duration {
+	if len(b.retryDelays) == 0 {
+		return time.Second
+	}
+	tier := attempt - 1
+	if tier < 0 {
+		tier = 0
+	}
+	if tier >= len(b.retryDelays) {
+		tier = len(b.retryDelays) - 1
+	}
+	return b.retryDelays[tier]
+}
+
+// publishDeadLetter republishes body+headers onto the DLQ stream,
+// stamping cause.
+func (b *NATSBroker) publishDeadLetter(ctx context.Context, body []byte, headers map[string]string, cause string) error {
+	msg := nats.NewMsg(b.dlqSubject)
+	msg.Data = body
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+	msg.Header.Set(errorHeader, cause)
+
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("broker: dead-letter message: %w", err)
+	}
+	return nil
+}
+
+// publishDeclined republishes body+headers onto the declined stream,
+// stamping reason.
+func (b *NATSBroker) publishDeclined(ctx context.Context, body []byte, headers map[string]string, reason string) error {
+	msg := nats.NewMsg(b.declinedSubject)
+	msg.Data = body
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+	msg.Header.Set(errorHeader, reason)
+
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("broker: route declined message: %w", err)
+	}
+	return nil
+}
+
+func (b *NATSBroker) DeadLetterDepth(ctx context.Context) (int, error) {
+	stream, err := b.js.Stream(ctx, b.dlqStream)
+	if err != nil {
+		return 0, fmt.Errorf("broker: inspect DLQ stream: %w", err)
+	}
+	info, err := stream.Info(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("broker: get DLQ stream info: %w", err)
+	}
+	return int(info.State.Msgs), nil
+}
+
+func (b *NATSBroker) Close() error {
+	if b.consCtx != nil {
+		b.consCtx.Stop()
+	}
+	b.nc.Close()
+	return nil
+}
+
+type natsMessage struct {
+	msg    jetstream.Msg
+	broker *NATSBroker
+}
+
+func (m *natsMessage) Body() []byte { return m.msg.Data() }
+
+func (m *natsMessage) Headers() map[string]string {
+	headers := make(map[string]string, len(m.msg.Headers()))
+	for k, v := range m.msg.Headers() {
+		if len(v) > 0 {
+			headers[k] = v[0]
+		}
+	}
+	return headers
+}
+
+func (m *natsMessage) Ack() error { return m.msg.Ack() }
+func (m *natsMessage) Nak() error { return m.msg.Nak() }
+
+func (m *natsMessage) RetryCount() int {
+	meta, err := m.msg.Metadata()
+	if err != nil || meta.NumDelivered == 0 {
+		return 0
+	}
+	return int(meta.NumDelivered) - 1
+}
+
+func (m *natsMessage) Retry(ctx context.Context, attempt int, cause string) error {
+	return m.msg.NakWithDelay(m.broker.retryDelay(attempt))
+}
+
+func (m *natsMessage) DeadLetter(ctx context.Context, cause string) error {
+	if err := m.broker.publishDeadLetter(ctx, m.msg.Data(), m.Headers(), cause); err != nil {
+		return err
+	}
+	return m.msg.Ack()
+}
+
+func (m *natsMessage) Declined(ctx context.Context, reason string) error {
+	if err := m.broker.publishDeclined(ctx, m.msg.Data(), m.Headers(), reason); err != nil {
+		return err
+	}
+	return m.msg.Ack()
+}
@@ -0,0 +1,451 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+// logger is this package's structured JSON logger, matching the one
+// package main uses so broker-level events (reconnects, channel
+// closures) show up with the same JSON shape as the rest of the
+// service's logs.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Str("service", "warehouse-service").Logger()
+
+const (
+	// rabbitMQConsumerTag identifies our consumer so Cancel can stop it by
+	// name rather than canceling every consumer on the channel.
+	rabbitMQConsumerTag = "warehouse-worker-pool"
+
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+
+	// retryExchangeSuffix and dlqSuffix name the retry/dead-letter
+	// topology relative to the main queue, e.g. "warehouse_orders.retry"
+	// and "warehouse_orders.dlq".
+	retryExchangeSuffix = ".retry"
+	dlqSuffix           = ".dlq"
+	declinedSuffix      = ".declined"
+
+	retryCountHeader = "x-retry-count"
+	errorHeader      = "x-error"
+)
+
+// RabbitMQConfig configures a RabbitMQBroker.
+type RabbitMQConfig struct {
+	URL   string
+	Queue string
+	// PrefetchCount is the channel's QoS prefetch count, shared across
+	// however many workers end up consuming from it.
+	PrefetchCount int
+	// RetryDelays is the backoff schedule for Message.Retry: attempt 1
+	// waits RetryDelays[0], attempt 2 waits RetryDelays[1], and so on,
+	// clamped to the last entry for attempts beyond the end of the
+	// slice. Each delay gets its own dead-letter queue that re-delivers
+	// into Queue once its TTL expires.
+	RetryDelays []time.Duration
+}
+
+// RabbitMQBroker is a Broker backed by RabbitMQ via amqp091-go, bound to
+// a single durable queue plus the retry-tier, dead-letter, and declined
+// queues needed by Message.Retry/DeadLetter/Declined. If the connection
+// drops mid-run, Consume reconnects with exponential backoff, redeclares this whole
+// topology, and keeps delivering onto the same channel it originally
+// returned, so callers never see the disconnect.
+type RabbitMQBroker struct {
+	url         string
+	queue       string
+	prefetch    int
+	retryDelays []time.Duration
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	ch        *amqp.Channel
+	observers []func(*amqp.Channel) error
+
+	// reconnects counts how many times connect has run with isReconnect
+	// set, for the warehouse_rabbitmq_reconnects_total metric.
+	reconnects int64
+}
+
+// NewRabbitMQBroker connects to cfg.URL and declares the main queue, the
+// retry-tier queues bound to a "<queue>.retry" direct exchange, a
+// terminal "<queue>.dlq" queue, and a terminal "<queue>.declined" queue.
+// The channel's QoS is set to cfg.PrefetchCount so multiple workers
+// share load fairly.
+func NewRabbitMQBroker(cfg RabbitMQConfig) (*RabbitMQBroker, error) {
+	b := &RabbitMQBroker{
+		url:         cfg.URL,
+		queue:       cfg.Queue,
+		prefetch:    cfg.PrefetchCount,
+		retryDelays: cfg.RetryDelays,
+	}
+	if err := b.connect(false); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AddReconnectObserver registers fn to be called with the new channel
+// every time the broker reconnects, so other components sharing this
+// connection (e.g. a future outbound publisher) get a chance to
+// redeclare their own exchanges and queues on it. fn is not called for
+// the initial connect, only for reconnects.
+func (b *RabbitMQBroker) AddReconnectObserver(fn func(ch *amqp.Channel) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.observers = append(b.observers, fn)
+}
+
+// connect dials, opens a channel, and declares the full queue/retry/DLQ
+// topology. Called at construction and again, with isReconnect set,
+// after every unexpected disconnect.
+func (b *RabbitMQBroker) connect(isReconnect bool) error {
+	conn, err := amqp.Dial(b.url)
+	if err != nil {
+		return fmt.Errorf("broker: connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("broker: open channel: %w", err)
+	}
+
+	if err := b.declareTopology(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
+	}
+
+	if err := ch.Qos(b.prefetch, 0, false); err != nil {
+		ch.Close()
+		conn.Close()
+		return fmt.Errorf("broker: set QoS: %w", err)
+	}
+
+	b.mu.Lock()
+	b.conn, b.ch = conn, ch
+	observers := append([]func(*amqp.Channel) error(nil), b.observers...)
+	b.mu.Unlock()
+
+	if isReconnect {
+		atomic.AddInt64(&b.reconnects, 1)
+		for _, notify := range observers {
+			if err := notify(ch); err != nil {
+				logger.Error().Err(err).Msg("broker: reconnect observer failed")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Reconnects reports how many times the broker has reconnected after an
+// unexpected disconnect, for the warehouse_rabbitmq_reconnects_total
+// metric.
+func (b *RabbitMQBroker) Reconnects() int64 {
+	return atomic.LoadInt64(&b.reconnects)
+}
+
+// declareTopology declares the main queue, a "<queue>.retry" direct
+// exchange with one delay queue per entry in b.retryDelays (bound by
+// routing key = tier index), and a terminal "<queue>.dlq" queue. Each
+// delay queue's TTL expiry dead-letters straight back into the main
+// queue via the default exchange, so a message that finishes waiting
+// out its backoff is redelivered automatically.
+func (b *RabbitMQBroker) declareTopology(ch *amqp.Channel) error {
+	if _, err := ch.QueueDeclare(b.queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("broker: declare queue %s: %w", b.queue, err)
+	}
+
+	retryExchange := b.queue + retryExchangeSuffix
+	if err := ch.ExchangeDeclare(retryExchange, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("broker: declare retry exchange %s: %w", retryExchange, err)
+	}
+
+	for tier, delay := range b.retryDelays {
+		retryQueue := fmt.Sprintf("%s.%d", retryExchange, tier)
+		args := amqp.Table{
+			"x-message-ttl":             int32(delay.Milliseconds()),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": b.queue,
+		}
+		if _, err := ch.QueueDeclare(retryQueue, true, false, false, false, args); err != nil {
+			return fmt.Errorf("broker: declare retry queue %s: %w", retryQueue, err)
+		}
+		if err := ch.QueueBind(retryQueue, strconv.Itoa(tier), retryExchange, false, nil); err != nil {
+			return fmt.Errorf("broker: bind retry queue %s: %w", retryQueue, err)
+		}
+	}
+
+	dlq := b.queue + dlqSuffix
+	if _, err := ch.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("broker: declare DLQ %s: %w", dlq, err)
+	}
+
+	declined := b.queue + declinedSuffix
+	if _, err := ch.QueueDeclare(declined, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("broker: declare declined queue %s: %w", declined, err)
+	}
+
+	return nil
+}
+
+func (b *RabbitMQBroker) channel() *amqp.Channel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ch
+}
+
+func (b *RabbitMQBroker) Consume(ctx context.Context) (<-chan Message, error) {
+	out := make(chan Message)
+	go b.runConsumeLoop(ctx, out)
+	return out, nil
+}
+
+// runConsumeLoop (re)subscribes to the queue and forwards deliveries to
+// out until ctx is canceled, reconnecting whenever the channel closes
+// unexpectedly.
+func (b *RabbitMQBroker) runConsumeLoop(ctx context.Context, out chan<- Message) {
+	defer close(out)
+
+	backoff := minReconnectBackoff
+	for {
+		ch := b.channel()
+		deliveries, err := ch.Consume(b.queue, rabbitMQConsumerTag, false, false, false, false, nil)
+		if err != nil {
+			logger.Error().Err(err).Str("queue", b.queue).Msg("broker: consume failed")
+			if !b.waitOrDone(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff, maxReconnectBackoff)
+			continue
+		}
+		closeNotify := ch.NotifyClose(make(chan *amqp.Error, 1))
+		backoff = minReconnectBackoff
+
+		if done := b.forward(ctx, deliveries, closeNotify, out); done {
+			return
+		}
+
+		logger.Error().Msg("broker: connection to RabbitMQ lost, reconnecting")
+		for {
+			if err := b.connect(true); err == nil {
+				break
+			} else {
+				logger.Error().Err(err).Msg("broker: reconnect failed")
+			}
+			if !b.waitOrDone(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff, maxReconnectBackoff)
+		}
+	}
+}
+
+// forward relays deliveries to out until ctx is canceled, in which case
+// it cancels the consumer, drains what's already buffered, and reports
+// done so the caller can shut down cleanly. If the channel reports an
+// unexpected close first, it reports not-done so the caller reconnects.
+func (b *RabbitMQBroker) forward(ctx context.Context, deliveries <-chan amqp.Delivery, closeNotify <-chan *amqp.Error, out chan<- Message) (done bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			b.channel().Cancel(rabbitMQConsumerTag, false)
+			for d := range deliveries {
+				out <- &rabbitMQMessage{delivery: d, broker: b}
+			}
+			return true
+		case d, ok := <-deliveries:
+			if !ok {
+				return false
+			}
+			out <- &rabbitMQMessage{delivery: d, broker: b}
+		case err := <-closeNotify:
+			if err != nil {
+				logger.Error().Err(err).Msg("broker: channel closed")
+			}
+			return false
+		}
+	}
+}
+
+func (b *RabbitMQBroker) waitOrDone(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next > max {
+		return max
+	}
+	return next
+}
+
+func (b *RabbitMQBroker) Publish(ctx context.Context, body []byte, headers map[string]string) error {
+	table := amqp.Table{}
+	for k, v := range headers {
+		table[k] = v
+	}
+	return b.channel().PublishWithContext(ctx, "", b.queue, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         body,
+		Headers:      table,
+	})
+}
+
+// publishRetry re-publishes body+headers to the retry exchange, routed
+// to the delay queue for attempt's tier, stamping the retry count and
+// cause.
+func (b *RabbitMQBroker) publishRetry(ctx context.Context, attempt int, body []byte, headers amqp.Table, cause string) error {
+	tier := attempt - 1
+	if tier < 0 {
+		tier = 0
+	}
+	if tier >= len(b.retryDelays) {
+		tier = len(b.retryDelays) - 1
+	}
+	if tier < 0 {
+		return fmt.Errorf("broker: no retry tiers configured")
+	}
+
+	table := amqp.Table{}
+	for k, v := range headers {
+		table[k] = v
+	}
+	table[retryCountHeader] = int32(attempt)
+	table[errorHeader] = cause
+
+	retryExchange := b.queue + retryExchangeSuffix
+	return b.channel().PublishWithContext(ctx, retryExchange, strconv.Itoa(tier), false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         body,
+		Headers:      table,
+	})
+}
+
+// publishDeadLetter re-publishes body+headers to the terminal DLQ,
+// stamping cause.
+func (b *RabbitMQBroker) publishDeadLetter(ctx context.Context, body []byte, headers amqp.Table, cause string) error {
+	table := amqp.Table{}
+	for k, v := range headers {
+		table[k] = v
+	}
+	table[errorHeader] = cause
+
+	return b.channel().PublishWithContext(ctx, "", b.queue+dlqSuffix, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         body,
+		Headers:      table,
+	})
+}
+
+// publishDeclined re-publishes body+headers to the terminal declined
+// queue, stamping reason.
+func (b *RabbitMQBroker) publishDeclined(ctx context.Context, body []byte, headers amqp.Table, reason string) error {
+	table := amqp.Table{}
+	for k, v := range headers {
+		table[k] = v
+	}
+	table[errorHeader] = reason
+
+	return b.channel().PublishWithContext(ctx, "", b.queue+declinedSuffix, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         body,
+		Headers:      table,
+	})
+}
+
+func (b *RabbitMQBroker) DeadLetterDepth(ctx context.Context) (int, error) {
+	q, err := b.channel().QueueInspect(b.queue + dlqSuffix)
+	if err != nil {
+		return 0, fmt.Errorf("broker: inspect DLQ: %w", err)
+	}
+	return q.Messages, nil
+}
+
+func (b *RabbitMQBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.ch.Close(); err != nil {
+		return err
+	}
+	return b.conn.Close()
+}
+
+type rabbitMQMessage struct {
+	delivery amqp.Delivery
+	broker   *RabbitMQBroker
+}
+
+func (m *rabbitMQMessage) Body() []byte { return m.delivery.Body }
+
+func (m *rabbitMQMessage) Headers() map[string]string {
+	headers := make(map[string]string, len(m.delivery.Headers))
+	for k, v := range m.delivery.Headers {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+		}
+	}
+	return headers
+}
+
+func (m *rabbitMQMessage) Ack() error { return m.delivery.Ack(false) }
+func (m *rabbitMQMessage) Nak() error { return m.delivery.Nack(false, false) }
+
+func (m *rabbitMQMessage) RetryCount() int {
+	v, ok := m.delivery.Headers[retryCountHeader]
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case int16:
+		return int(n)
+	case int:
+		return n
+	default:
+		return 0
+	}
+}
+
+func (m *rabbitMQMessage) Retry(ctx context.Context, attempt int, cause string) error {
+	if err := m.broker.publishRetry(ctx, attempt, m.delivery.Body, m.delivery.Headers, cause); err != nil {
+		return fmt.Errorf("broker: schedule retry: %w", err)
+	}
+	return m.delivery.Ack(false)
+}
+
+func (m *rabbitMQMessage) DeadLetter(ctx context.Context, cause string) error {
+	if err := m.broker.publishDeadLetter(ctx, m.delivery.Body, m.delivery.Headers, cause); err != nil {
+		return fmt.Errorf("broker: dead-letter message: %w", err)
+	}
+	return m.delivery.Ack(false)
+}
+
+func (m *rabbitMQMessage) Declined(ctx context.Context, reason string) error {
+	if err := m.broker.publishDeclined(ctx, m.delivery.Body, m.delivery.Headers, reason); err != nil {
+		return fmt.Errorf("broker: route declined message: %w", err)
+	}
+	return m.delivery.Ack(false)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// logger is this service's structured JSON logger, replacing the
+// standard library's log package everywhere in warehouse-service so an
+// external log pipeline gets consistent JSON fields (worker_id,
+// order_id, cart_id, correlation_id) instead of parsing free-text
+// messages.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Str("service", "warehouse-service").Logger()
+
+// logOrder writes one structured order-processing log line. level is
+// "info" or "error"; order and err are optional and only included when
+// non-nil.
+func logOrder(level string, workerID int, order *WarehouseOrder, correlationID, msg string, err error) {
+	event := logger.WithLevel(zerologLevel(level)).
+		Int("worker_id", workerID).
+		Str("correlation_id", correlationID)
+	if order != nil {
+		event = event.Str("order_id", order.OrderID).Str("cart_id", order.CartID)
+	}
+	if err != nil {
+		event = event.Err(err)
+	}
+	event.Msg(msg)
+}
+
+func zerologLevel(level string) zerolog.Level {
+	if level == "error" {
+		return zerolog.ErrorLevel
+	}
+	return zerolog.InfoLevel
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"warehouse-service/broker"
+)
+
+var (
+	paymentAuthorizationTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "warehouse_payment_authorization_total",
+			Help: "Number of payment re-authorization attempts by outcome",
+		},
+		[]string{"outcome"},
+	)
+
+	ordersProcessedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "warehouse_orders_processed_total",
+			Help: "Number of order messages handled by this instance, by outcome",
+		},
+		[]string{"status"},
+	)
+
+	orderProcessingDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "warehouse_order_processing_seconds",
+			Help: "Time to handle a single order message, from delivery to ack or terminal routing",
+		},
+		[]string{"status"},
+	)
+
+	productQuantityGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "warehouse_product_quantity",
+			Help: "Cumulative quantity processed per product",
+		},
+		[]string{"product_id"},
+	)
+)
+
+// recordPaymentAuthorization increments paymentAuthorizationTotal with
+// the given outcome (e.g. "authorized", "declined", "circuit_open",
+// "error").
+func recordPaymentAuthorization(outcome string) {
+	paymentAuthorizationTotal.WithLabelValues(outcome).Inc()
+}
+
+// recordOrderProcessed increments warehouse_orders_processed_total and
+// records warehouse_order_processing_seconds for the given status (e.g.
+// "success", "duplicate", "payment_rejected", "error").
+func recordOrderProcessed(status string, duration time.Duration) {
+	ordersProcessedTotal.WithLabelValues(status).Inc()
+	orderProcessingDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
+
+// setProductQuantityGauge sets this instance's productQty mirror
+// (ProductID -> cumulative quantity processed) on the exported gauge.
+// Called every time productQty changes rather than observed on scrape,
+// since Prometheus counters/gauges here are all push-style.
+func setProductQuantityGauge(productID string, qty int64) {
+	productQuantityGauge.WithLabelValues(productID).Set(float64(qty))
+}
+
+// rabbitmqReconnectsTotal reports how many times the RabbitMQ connection
+// has reconnected after an unexpected disconnect. Only updated when the
+// broker is RabbitMQ-backed; NATS's client library handles reconnects
+// below the Broker abstraction entirely, so there's nothing equivalent
+// to report there.
+var rabbitmqReconnectsTotal = promauto.NewGaugeFunc(
+	prometheus.GaugeOpts{
+		Name: "warehouse_rabbitmq_reconnects_total",
+		Help: "Number of times the RabbitMQ connection has reconnected after an unexpected disconnect",
+	},
+	func() float64 { return 0 },
+)
+
+// registerRabbitMQReconnectsGauge re-registers
+// warehouse_rabbitmq_reconnects_total as a GaugeFunc reading b.Reconnects()
+// live on every scrape, replacing the zero-value placeholder created at
+// package init (b isn't available until main constructs the broker).
+func registerRabbitMQReconnectsGauge(b *broker.RabbitMQBroker) {
+	prometheus.Unregister(rabbitmqReconnectsTotal)
+	rabbitmqReconnectsTotal = promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "warehouse_rabbitmq_reconnects_total",
+			Help: "Number of times the RabbitMQ connection has reconnected after an unexpected disconnect",
+		},
+		func() float64 { return float64(b.Reconnects()) },
+	)
+}
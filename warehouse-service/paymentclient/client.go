@@ -0,0 +1,145 @@
+// Package paymentclient calls the Credit Card Authorizer's /authorize
+// endpoint before an order is committed to inventory, through the same
+// resilient httpclient used for other flaky downstream calls in this
+// codebase: bounded retries plus a per-endpoint circuit breaker, so a
+// struggling authorizer can't back up the warehouse queue indefinitely.
+package paymentclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"warehouse-service/httpclient"
+)
+
+// Decision is the outcome of an Authorize call.
+type Decision string
+
+const (
+	Authorized Decision = "authorized"
+	Declined   Decision = "declined"
+)
+
+// ErrCircuitOpen is returned by Authorize when the breaker for the
+// payment service is open. Callers should treat this like a transient
+// outage (retry after a short delay) rather than a decline.
+var ErrCircuitOpen = httpclient.ErrCircuitOpen
+
+// authRequest mirrors the Credit Card Authorizer's /authorize request
+// body.
+type authRequest struct {
+	CreditCardNumber string  `json:"credit_card_number"`
+	Amount           float64 `json:"amount"`
+}
+
+// authResponse mirrors the Credit Card Authorizer's /authorize response
+// body.
+type authResponse struct {
+	Status        string `json:"status"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// Config configures a Client.
+type Config struct {
+	// BaseURL is the Credit Card Authorizer's base URL, e.g.
+	// "http://localhost:8083".
+	BaseURL string
+	// Timeout bounds a single /authorize attempt.
+	Timeout time.Duration
+	// MaxRetries is the number of additional attempts after the first one.
+	MaxRetries int
+}
+
+// Client calls the Credit Card Authorizer's /authorize endpoint.
+type Client struct {
+	baseURL string
+	http    *httpclient.Client
+}
+
+// New returns a Client pointed at cfg.BaseURL.
+func New(cfg Config) *Client {
+	return &Client{
+		baseURL: cfg.BaseURL,
+		http: httpclient.New(httpclient.Config{
+			MaxRetries: cfg.MaxRetries,
+			Timeout:    cfg.Timeout,
+		}),
+	}
+}
+
+// correlationIDHeader is the same header name warehouse-service's main.go
+// reads off the incoming order message, forwarded here so the
+// authorizer's own logs can be correlated with the order that triggered
+// the call.
+const correlationIDHeader = "X-Correlation-ID"
+
+// Authorize requests authorization for amount against creditCardNumber,
+// returning Authorized or Declined along with the authorizer's message.
+// correlationID is forwarded as the X-Correlation-ID header when
+// non-empty; it has no bearing on the authorization decision. A
+// transport error or ErrCircuitOpen is returned as err rather than a
+// Decision, since the caller routes those down a different path
+// (respectively: retry-with-backoff, and a short immediate requeue)
+// than an explicit decline.
+func (c *Client) Authorize(ctx context.Context, creditCardNumber string, amount float64, correlationID string) (Decision, string, error) {
+	body, err := json.Marshal(authRequest{CreditCardNumber: creditCardNumber, Amount: amount})
+	if err != nil {
+		return "", "", fmt.Errorf("paymentclient: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/authorize", bytes.NewReader(body))
+	if err != nil {
+		return "", "", fmt.Errorf("paymentclient: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if correlationID != "" {
+		req.Header.Set(correlationIDHeader, correlationID)
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	resp, err := c.http.Do(ctx, req)
+	if err != nil {
+		if errors.Is(err, httpclient.ErrCircuitOpen) {
+			return "", "", ErrCircuitOpen
+		}
+		return "", "", fmt.Errorf("paymentclient: call authorizer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var parsed authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", "", fmt.Errorf("paymentclient: decode response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusPaymentRequired {
+		return Declined, parsed.Message, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("paymentclient: authorizer returned status %d: %s", resp.StatusCode, parsed.Message)
+	}
+	return Authorized, parsed.Message, nil
+}
+
+// BreakerState reports the circuit breaker state for the authorizer's
+// /authorize endpoint, or "unknown" before the first call is made.
+func (c *Client) BreakerState() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "unknown"
+	}
+	state, known := c.http.BreakerState(http.MethodPost, u.Host, "/authorize")
+	if !known {
+		return "unknown"
+	}
+	return state.String()
+}
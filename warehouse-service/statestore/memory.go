@@ -0,0 +1,115 @@
+package statestore
+
+import (
+	"context"
+	"sync"
+)
+
+// authClaim is the state of one order's authorization claim, as tracked
+// by MemoryStore's authorized map.
+type authClaim struct {
+	committed  bool
+	authorized bool
+}
+
+// MemoryStore is a mutex-protected in-memory Store. Totals don't survive
+// a restart; use NATSKVStore when they need to.
+type MemoryStore struct {
+	mu         sync.Mutex
+	seen       map[string]struct{}
+	authorized map[string]*authClaim
+	totalOrder int64
+	productQty map[string]int64
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		seen:       make(map[string]struct{}),
+		authorized: make(map[string]*authClaim),
+		productQty: make(map[string]int64),
+	}
+}
+
+func (s *MemoryStore) SeenOrder(ctx context.Context, orderID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.seen[orderID]
+	return ok, nil
+}
+
+func (s *MemoryStore) RecordOrder(ctx context.Context, orderID string, items []Item) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seen[orderID]; ok {
+		return false, nil
+	}
+	s.seen[orderID] = struct{}{}
+	s.totalOrder++
+	for _, item := range items {
+		s.productQty[item.ProductID] += int64(item.Quantity)
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) ClaimAuthorization(ctx context.Context, orderID string) (AuthorizationStatus, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claim, ok := s.authorized[orderID]
+	if !ok {
+		s.authorized[orderID] = &authClaim{}
+		return AuthorizationPending, true, nil
+	}
+	if !claim.committed {
+		return AuthorizationPending, false, nil
+	}
+	if claim.authorized {
+		return AuthorizationAuthorized, false, nil
+	}
+	return AuthorizationDeclined, false, nil
+}
+
+func (s *MemoryStore) CommitAuthorization(ctx context.Context, orderID string, authorized bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authorized[orderID] = &authClaim{committed: true, authorized: authorized}
+	return nil
+}
+
+func (s *MemoryStore) ReleaseAuthorizationClaim(ctx context.Context, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.authorized, orderID)
+	return nil
+}
+
+func (s *MemoryStore) IncrementProduct(ctx context.Context, productID string, quantity int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.productQty[productID] += int64(quantity)
+	return nil
+}
+
+func (s *MemoryStore) TotalOrders(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.totalOrder, nil
+}
+
+func (s *MemoryStore) ProductQuantities(ctx context.Context) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]int64, len(s.productQty))
+	for k, v := range s.productQty {
+		out[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,330 @@
+package statestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// maxCASRetries bounds how many times IncrementProduct and RecordOrder's
+// order-counter update retry after losing an optimistic-concurrency race
+// on a KV entry, the same bound cartstore.PostgresStore.AddItem uses for
+// its own version-conflict retries.
+const maxCASRetries = 5
+
+const (
+	seenKeyPrefix    = "seen."
+	authKeyPrefix    = "auth."
+	totalOrdersKey   = "counter.total"
+	productKeyPrefix = "counter.product."
+)
+
+// NATSKVStore is a Store backed by a NATS JetStream key/value bucket. It
+// has no native atomic-increment operation, so counters use the same
+// read-revision/Update-with-that-revision/retry-on-conflict idiom
+// cartstore.PostgresStore.addItemOnce uses for its version column,
+// instead of a RabbitMQ/Postgres-style transaction.
+type NATSKVStore struct {
+	nc *nats.Conn
+	kv jetstream.KeyValue
+}
+
+// NewNATSKVStore connects to url and creates (or reuses) the KV bucket
+// named bucket.
+func NewNATSKVStore(ctx context.Context, url, bucket string) (*NATSKVStore, error) {
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("statestore: connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("statestore: create jetstream context: %w", err)
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: bucket})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("statestore: create/update KV bucket %s: %w", bucket, err)
+	}
+
+	return &NATSKVStore{nc: nc, kv: kv}, nil
+}
+
+func (s *NATSKVStore) SeenOrder(ctx context.Context, orderID string) (bool, error) {
+	_, err := s.kv.Get(ctx, seenKeyPrefix+orderID)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("statestore: check seen order %s: %w", orderID, err)
+	}
+	return true, nil
+}
+
+// seenRecord is the value stored at seenKeyPrefix+orderID. Committed is
+// false from the moment the order is claimed until its totals have been
+// folded in; storing Items alongside it is what lets a later call finish
+// a claim it didn't start (see RecordOrder).
+type seenRecord struct {
+	Committed bool   `json:"committed"`
+	Items     []Item `json:"items"`
+}
+
+// RecordOrder marks orderID seen and folds items into the running
+// totals. The seen marker is written with Create, which fails if the key
+// already exists; a redelivered order that races its way past
+// SeenOrder's check lands here too.
+//
+// A bare "1" marker isn't enough to make this safe against a crash
+// between the Create and the counter increments below: the order would
+// be permanently marked seen with its quantities never counted, and no
+// future redelivery would retry it (Create would just hit ErrKeyExists
+// and return success). Instead the seen record carries Items and a
+// Committed flag, so a redelivery that finds an uncommitted record
+// recognizes a prior attempt was interrupted and finishes the increments
+// itself rather than treating the order as already handled. Re-claiming
+// the record via a revision-checked Update before doing so means a
+// second worker racing the same recovery loses the CAS and backs off
+// instead of also applying the increments; the one narrow window left
+// is a crash between the increments completing and the final Committed
+// write, which at worst redoes those increments once on the next
+// redelivery -- a much smaller risk than the permanent loss this
+// replaces.
+func (s *NATSKVStore) RecordOrder(ctx context.Context, orderID string, items []Item) (bool, error) {
+	key := seenKeyPrefix + orderID
+
+	payload, err := json.Marshal(seenRecord{Committed: false, Items: items})
+	if err != nil {
+		return false, fmt.Errorf("statestore: marshal seen record for order %s: %w", orderID, err)
+	}
+
+	revision, err := s.kv.Create(ctx, key, payload)
+	if err != nil {
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			return false, fmt.Errorf("statestore: mark order %s seen: %w", orderID, err)
+		}
+
+		entry, getErr := s.kv.Get(ctx, key)
+		if getErr != nil {
+			return false, fmt.Errorf("statestore: get seen record for order %s: %w", orderID, getErr)
+		}
+		var existing seenRecord
+		if err := json.Unmarshal(entry.Value(), &existing); err != nil {
+			return false, fmt.Errorf("statestore: decode seen record for order %s: %w", orderID, err)
+		}
+		if existing.Committed {
+			return false, nil
+		}
+
+		claimed, err := s.kv.Update(ctx, key, entry.Value(), entry.Revision())
+		if err != nil {
+			// Lost the race to re-claim an interrupted record to
+			// another worker doing the same recovery; let it finish.
+			return false, nil
+		}
+		revision = claimed
+		items = existing.Items
+	}
+
+	if err := s.casIncrement(ctx, totalOrdersKey, 1); err != nil {
+		return false, fmt.Errorf("statestore: increment total orders: %w", err)
+	}
+	for _, item := range items {
+		if err := s.IncrementProduct(ctx, item.ProductID, item.Quantity); err != nil {
+			return false, err
+		}
+	}
+
+	committed, err := json.Marshal(seenRecord{Committed: true, Items: items})
+	if err != nil {
+		return true, fmt.Errorf("statestore: marshal committed record for order %s: %w", orderID, err)
+	}
+	if _, err := s.kv.Update(ctx, key, committed, revision); err != nil {
+		return true, fmt.Errorf("statestore: mark order %s committed: %w", orderID, err)
+	}
+	return true, nil
+}
+
+// authRecord is the value stored at authKeyPrefix+orderID. It starts
+// uncommitted the moment ClaimAuthorization reserves the order for an
+// authorization attempt, and is only ever marked Committed by
+// CommitAuthorization once payments.Authorize has actually returned a
+// decision -- see ClaimAuthorization's doc comment for why an uncommitted
+// record found on a later call is treated as indeterminate rather than
+// retried.
+type authRecord struct {
+	Committed  bool `json:"committed"`
+	Authorized bool `json:"authorized"`
+}
+
+// ClaimAuthorization reserves orderID by writing an uncommitted authRecord
+// with Create, which fails if the key already exists. That's the only
+// case where claimed is true; every other outcome reports the status a
+// prior call already established (see the Store interface doc comment).
+func (s *NATSKVStore) ClaimAuthorization(ctx context.Context, orderID string) (AuthorizationStatus, bool, error) {
+	key := authKeyPrefix + orderID
+
+	payload, err := json.Marshal(authRecord{})
+	if err != nil {
+		return AuthorizationPending, false, fmt.Errorf("statestore: marshal auth claim for order %s: %w", orderID, err)
+	}
+
+	if _, err := s.kv.Create(ctx, key, payload); err == nil {
+		return AuthorizationPending, true, nil
+	} else if !errors.Is(err, jetstream.ErrKeyExists) {
+		return AuthorizationPending, false, fmt.Errorf("statestore: claim authorization for order %s: %w", orderID, err)
+	}
+
+	entry, err := s.kv.Get(ctx, key)
+	if err != nil {
+		return AuthorizationPending, false, fmt.Errorf("statestore: get auth record for order %s: %w", orderID, err)
+	}
+	var existing authRecord
+	if err := json.Unmarshal(entry.Value(), &existing); err != nil {
+		return AuthorizationPending, false, fmt.Errorf("statestore: decode auth record for order %s: %w", orderID, err)
+	}
+	if !existing.Committed {
+		return AuthorizationPending, false, nil
+	}
+	if existing.Authorized {
+		return AuthorizationAuthorized, false, nil
+	}
+	return AuthorizationDeclined, false, nil
+}
+
+// CommitAuthorization records orderID's payment decision over the
+// uncommitted record ClaimAuthorization wrote.
+func (s *NATSKVStore) CommitAuthorization(ctx context.Context, orderID string, authorized bool) error {
+	key := authKeyPrefix + orderID
+
+	entry, err := s.kv.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("statestore: get auth record for order %s: %w", orderID, err)
+	}
+	payload, err := json.Marshal(authRecord{Committed: true, Authorized: authorized})
+	if err != nil {
+		return fmt.Errorf("statestore: marshal committed auth record for order %s: %w", orderID, err)
+	}
+	if _, err := s.kv.Update(ctx, key, payload, entry.Revision()); err != nil {
+		return fmt.Errorf("statestore: commit authorization for order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// ReleaseAuthorizationClaim deletes orderID's claim so a later redelivery
+// can claim and attempt authorization again, used when
+// payments.Authorize was never actually reached (circuit open, transport
+// error) rather than left genuinely indeterminate.
+func (s *NATSKVStore) ReleaseAuthorizationClaim(ctx context.Context, orderID string) error {
+	if err := s.kv.Delete(ctx, authKeyPrefix+orderID); err != nil {
+		return fmt.Errorf("statestore: release authorization claim for order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+func (s *NATSKVStore) IncrementProduct(ctx context.Context, productID string, quantity int) error {
+	if err := s.casIncrement(ctx, productKeyPrefix+productID, int64(quantity)); err != nil {
+		return fmt.Errorf("statestore: increment product %s: %w", productID, err)
+	}
+	return nil
+}
+
+// casIncrement adds delta to the int64 counter stored at key, retrying
+// up to maxCASRetries times when Update's revision check loses a race
+// against a concurrent writer.
+func (s *NATSKVStore) casIncrement(ctx context.Context, key string, delta int64) error {
+	for attempt := 0; attempt < maxCASRetries; attempt++ {
+		entry, err := s.kv.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			if _, err := s.kv.Create(ctx, key, encodeCounter(delta)); err != nil {
+				if errors.Is(err, jetstream.ErrKeyExists) {
+					continue
+				}
+				return err
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		current, err := decodeCounter(entry.Value())
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.kv.Update(ctx, key, encodeCounter(current+delta), entry.Revision()); err != nil {
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("too many version conflicts on key %s", key)
+}
+
+func (s *NATSKVStore) TotalOrders(ctx context.Context) (int64, error) {
+	entry, err := s.kv.Get(ctx, totalOrdersKey)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("statestore: get total orders: %w", err)
+	}
+	return decodeCounter(entry.Value())
+}
+
+func (s *NATSKVStore) ProductQuantities(ctx context.Context) (map[string]int64, error) {
+	lister, err := s.kv.ListKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("statestore: list product keys: %w", err)
+	}
+
+	quantities := make(map[string]int64)
+	for key := range lister.Keys() {
+		productID, ok := trimPrefix(key, productKeyPrefix)
+		if !ok {
+			continue
+		}
+		entry, err := s.kv.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("statestore: get product quantity %s: %w", productID, err)
+		}
+		qty, err := decodeCounter(entry.Value())
+		if err != nil {
+			return nil, err
+		}
+		quantities[productID] = qty
+	}
+	return quantities, nil
+}
+
+// Close releases the underlying NATS connection.
+func (s *NATSKVStore) Close() error {
+	s.nc.Close()
+	return nil
+}
+
+func encodeCounter(v int64) []byte {
+	return []byte(strconv.FormatInt(v, 10))
+}
+
+func decodeCounter(b []byte) (int64, error) {
+	v, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("statestore: decode counter: %w", err)
+	}
+	return v, nil
+}
+
+func trimPrefix(key, prefix string) (string, bool) {
+	if len(key) <= len(prefix) || key[:len(prefix)] != prefix {
+		return "", false
+	}
+	return key[len(prefix):], true
+}
@@ -0,0 +1,88 @@
+// Package statestore defines the persistence interface for the
+// warehouse service's processing state, so main.go can swap between an
+// in-memory implementation and a durable one with the STATE_STORE
+// environment variable without changing processOneMessage or
+// processOrder.
+package statestore
+
+import "context"
+
+// Item is a line item in an order, as recorded by RecordOrder.
+type Item struct {
+	ProductID string
+	Quantity  int
+}
+
+// Store tracks which orders the warehouse service has already processed
+// and the running totals it reports on (totalOrders, productQty). A
+// worker that crashes between processOrder and msg.Ack will see the
+// order redelivered; SeenOrder is what lets processOneMessage recognize
+// that and skip-but-ack it instead of double-counting.
+type Store interface {
+	// SeenOrder reports whether orderID has already been recorded by a
+	// prior RecordOrder call.
+	SeenOrder(ctx context.Context, orderID string) (bool, error)
+	// RecordOrder marks orderID as seen and folds items into the running
+	// product-quantity totals and the order counter. It must be safe to
+	// call more than once for the same orderID (a worker that crashes
+	// after RecordOrder but before Ack will see the message redelivered
+	// and call RecordOrder again before SeenOrder catches it) without
+	// double-counting. recorded reports whether this call actually wrote
+	// the totals (true) or found the order already recorded and no-opped
+	// (false), so a caller mirroring the totals elsewhere (e.g. an
+	// in-memory gauge) knows not to bump its own copy on a no-op.
+	RecordOrder(ctx context.Context, orderID string, items []Item) (recorded bool, err error)
+	// IncrementProduct adds quantity to productID's running total.
+	// RecordOrder uses this for each item in the order; it's also
+	// exposed directly for any future adjustment (a return, a manual
+	// correction) that isn't tied to a specific order.
+	IncrementProduct(ctx context.Context, productID string, quantity int) error
+	// TotalOrders returns the number of orders RecordOrder has committed.
+	TotalOrders(ctx context.Context) (int64, error)
+	// ProductQuantities returns the running per-product quantity totals,
+	// used to rehydrate productQty on startup.
+	ProductQuantities(ctx context.Context) (map[string]int64, error)
+	// ClaimAuthorization reserves orderID for a payment authorization
+	// attempt before payments.Authorize is called, so a crash between a
+	// successful charge and CommitAuthorization leaves a record that a
+	// later redelivery can recognize instead of blindly re-authorizing.
+	// claimed is true only when this call created the reservation, in
+	// which case the caller must proceed to call payments.Authorize and
+	// then either CommitAuthorization (on a decision) or
+	// ReleaseAuthorizationClaim (on a circuit-open/transport error that
+	// never reached the authorizer). When claimed is false, status
+	// reports what a prior call already established:
+	//   - AuthorizationAuthorized / AuthorizationDeclined: that decision
+	//     was committed; the caller skips straight to the matching
+	//     outcome instead of calling payments.Authorize again.
+	//   - AuthorizationPending: a prior attempt claimed this order and
+	//     never released or committed it -- it's indeterminate whether
+	//     the card was actually charged, so the caller must not retry
+	//     the authorization and should fail safe instead (see main.go's
+	//     authorizePayment).
+	ClaimAuthorization(ctx context.Context, orderID string) (status AuthorizationStatus, claimed bool, err error)
+	// CommitAuthorization records orderID's payment decision against a
+	// claim made by ClaimAuthorization.
+	CommitAuthorization(ctx context.Context, orderID string, authorized bool) error
+	// ReleaseAuthorizationClaim removes an orderID's claim made by
+	// ClaimAuthorization when payments.Authorize was never actually
+	// reached (circuit open, transport error) so a later redelivery can
+	// claim and attempt it again instead of being treated as
+	// indeterminate.
+	ReleaseAuthorizationClaim(ctx context.Context, orderID string) error
+}
+
+// AuthorizationStatus is the state of an order's payment authorization as
+// tracked by ClaimAuthorization/CommitAuthorization.
+type AuthorizationStatus int
+
+const (
+	// AuthorizationPending means a claim exists but no decision has been
+	// committed against it yet.
+	AuthorizationPending AuthorizationStatus = iota
+	// AuthorizationAuthorized means a prior attempt committed a
+	// successful authorization.
+	AuthorizationAuthorized
+	// AuthorizationDeclined means a prior attempt committed a decline.
+	AuthorizationDeclined
+)
@@ -0,0 +1,86 @@
+// Package telemetry wires up OpenTelemetry tracing and metrics for the
+// service: an OTLP/gRPC exporter pointed at OTEL_EXPORTER_OTLP_ENDPOINT,
+// and the W3C traceparent propagator used to continue the trace the
+// shopping-cart service started at checkout, regardless of which broker
+// package carried the message here.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Shutdown flushes and closes the configured exporters. Callers should
+// defer it in main() after a successful Init.
+type Shutdown func(context.Context) error
+
+// Init configures the global trace and meter providers for serviceName,
+// exporting via OTLP/gRPC to OTEL_EXPORTER_OTLP_ENDPOINT (default
+// "localhost:4317"), and installs the W3C TraceContext propagator as the
+// global propagator.
+func Init(ctx context.Context, serviceName string) (Shutdown, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4317"
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: build resource: %w", err)
+	}
+
+	traceExporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: create trace exporter: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	metricExporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(endpoint),
+		otlpmetricgrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: create metric exporter: %w", err)
+	}
+
+	meterProvider := metric.NewMeterProvider(
+		metric.WithReader(metric.NewPeriodicReader(metricExporter, metric.WithInterval(15*time.Second))),
+		metric.WithResource(res),
+	)
+	otel.SetMeterProvider(meterProvider)
+
+	propagator := propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	)
+	otel.SetTextMapPropagator(propagator)
+
+	return func(shutdownCtx context.Context) error {
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return meterProvider.Shutdown(shutdownCtx)
+	}, nil
+}